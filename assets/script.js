@@ -14,11 +14,48 @@ window._resolveCallback = function (callbackId, result) {
 };
 
 // Обёртка для асинхронного вызова Go функции
-function solveProblem(text, showLog) {
+// options: { showLog, polish, translation }
+function solveProblem(text, options) {
     return new Promise((resolve) => {
         const callbackId = 'cb_' + (++window._callbackCounter);
         window._pendingCallbacks[callbackId] = resolve;
-        solveProblemAsync(text, showLog, callbackId);
+        solveProblemAsync(text, options, callbackId);
+    });
+}
+
+// Ручной режим: клаузы и формулы без LLM
+function manualSolve(text, options) {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        manualSolveAsync(text, options, callbackId);
+    });
+}
+
+// Первый этап: текст задачи -> список клауз { clauses, error }
+function formalizeProblem(text, translation) {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        formalizeAsync(text, translation, callbackId);
+    });
+}
+
+// Второй этап: доказательство на (исправленных) клаузах
+function proveClauses(text, clauses, glossary, options) {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        proveAsync(text, clauses, glossary, options, callbackId);
+    });
+}
+
+// Экспорт последней формализованной задачи в выбранный формат
+function exportProblem(format) {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        exportProblemAsync(format, callbackId);
     });
 }
 
@@ -58,6 +95,49 @@ function typeWriter(text, elementId) {
     });
 }
 
+// Состояние чекбоксов и настроек
+function solveOptions() {
+    return {
+        showLog: document.getElementById('showLog').checked,
+        polish: document.getElementById('polish').checked,
+        translation: document.getElementById('translation').value,
+        samples: parseInt(document.getElementById('samples').value, 10),
+    };
+}
+
+// SVG строится на стороне Go из доверенных данных движка
+function showProofTree(svg) {
+    const tree = document.getElementById('proofTree');
+    tree.innerHTML = svg || "";
+    tree.classList.toggle('visible', !!svg);
+}
+
+const INPUT_PLACEHOLDERS = {
+    text: "Введите ваш запрос...",
+    manual: "По одному утверждению в строке:\n∀x (Человек(x) → Смертен(x))\nЧеловек(Сократ)\n⊢ Смертен(Сократ)",
+};
+
+// В ручном режиме LLM не нужна: формализация и её правка недоступны
+function switchInputMode() {
+    const mode = document.getElementById('inputMode').value;
+    document.getElementById('input').placeholder = INPUT_PLACEHOLDERS[mode];
+    document.getElementById('formalizeBtn').disabled = mode === 'manual';
+    document.getElementById('clauseEditor').classList.remove('visible');
+    showInputErrors([]);
+}
+
+// Ошибки ручного ввода выводятся под полем ввода, по строкам
+function showInputErrors(errors) {
+    const box = document.getElementById('inputErrors');
+    box.innerHTML = "";
+    for (const e of errors || []) {
+        const line = document.createElement('div');
+        line.textContent = "Строка " + e.line + ": " + e.message;
+        box.appendChild(line);
+    }
+    box.classList.toggle('visible', !!errors && errors.length > 0);
+}
+
 async function processRequest() {
     const inputField = document.getElementById('input');
     const outputField = document.getElementById('output');
@@ -69,16 +149,22 @@ async function processRequest() {
     btn.classList.add('processing');
     btn.innerText = "PROCESSING...";
     outputField.textContent = ""; // Изменили текст
+    showProofTree("");
+    showVariants([]);
     inputField.disabled = true;
     btn.disabled = true;
 
     try {
-        // Получаем состояние чекбокса
-        const showLog = document.getElementById('showLog').checked;
-        
-        const response = await solveProblem(inputText, showLog);
+        const manual = document.getElementById('inputMode').value === 'manual';
+        const response = manual
+            ? await manualSolve(inputText, solveOptions())
+            : await solveProblem(inputText, solveOptions());
+
+        showInputErrors(response.errors);
+        showVariants(response.variants || []);
 
-        await typeWriter(response, 'output', 20);
+        showProofTree(response.svg);
+        await typeWriter(response.text, 'output', 20);
 
     } catch (error) {
         outputField.textContent = "Error: " + error;
@@ -89,4 +175,290 @@ async function processRequest() {
         btn.disabled = false;
         inputField.focus();
     }
+}
+
+async function processExport() {
+    const outputField = document.getElementById('output');
+    const btn = document.getElementById('exportBtn');
+    const format = document.getElementById('exportFormat').value;
+
+    btn.disabled = true;
+    try {
+        // Экспорт выводится целиком, без анимации — его удобнее сразу копировать
+        outputField.textContent = await exportProblem(format);
+    } catch (error) {
+        outputField.textContent = "Error: " + error;
+    } finally {
+        btn.disabled = false;
+    }
+}
+
+// Копирует доказательство в LaTeX (bussproofs) в буфер обмена
+async function copyLatex() {
+    const outputField = document.getElementById('output');
+    const btn = document.getElementById('latexBtn');
+
+    btn.disabled = true;
+    try {
+        const latex = await exportProblem('latex');
+        if (latex.startsWith("❌")) {
+            outputField.textContent = latex;
+            return;
+        }
+        try {
+            await navigator.clipboard.writeText(latex);
+            btn.innerText = "COPIED";
+            setTimeout(() => { btn.innerText = "COPY LATEX"; }, 1500);
+        } catch (e) {
+            // Буфер обмена недоступен — показываем текст для ручного копирования
+            outputField.textContent = latex;
+        }
+    } finally {
+        btn.disabled = false;
+    }
+}
+
+const VERDICT_LABELS = {
+    proved: "доказано",
+    not_proved: "не доказано",
+    timeout: "лимит",
+    inconsistent: "противоречие",
+};
+
+const ROLE_LABELS = {
+    axiom: "правило",
+    hypothesis: "факт",
+    negated_conjecture: "отрицание цели",
+};
+
+// Строка редактора: клауза, роль, исходное предложение и пересказ
+function addClauseRow(item) {
+    const row = document.createElement('div');
+    row.className = 'clause-row';
+
+    const input = document.createElement('input');
+    input.className = 'neu-inset clause-input';
+    input.value = item.clause;
+    input.dataset.source = item.source || "";
+
+    const role = document.createElement('select');
+    role.className = 'neu-select clause-role';
+    for (const [value, label] of Object.entries(ROLE_LABELS)) {
+        const option = document.createElement('option');
+        option.value = value;
+        option.textContent = label;
+        option.selected = (item.role || "axiom") === value;
+        role.appendChild(option);
+    }
+
+    const remove = document.createElement('button');
+    remove.className = 'neu-btn neu-btn-small';
+    remove.textContent = "✕";
+    remove.onclick = () => row.remove();
+
+    row.append(input, role, remove);
+
+    const notes = [];
+    if (item.translation) notes.push("→ " + item.translation);
+    if (item.source) notes.push("из: «" + item.source + "»");
+    if (notes.length > 0) {
+        const hint = document.createElement('div');
+        hint.className = 'clause-hint';
+        hint.textContent = notes.join("   ");
+        row.appendChild(hint);
+    }
+
+    document.getElementById('clauseList').appendChild(row);
+}
+
+// Непустые строки редактора — в том же порядке, что и collectClauses
+function filledClauseRows() {
+    return [...document.querySelectorAll('#clauseList .clause-row')]
+        .filter(row => row.querySelector('.clause-input').value.trim());
+}
+
+// Клаузы из редактора в формате ParsedClause (пустые строки пропускаются)
+function collectClauses() {
+    const clauses = [];
+    for (const row of filledClauseRows()) {
+        const input = row.querySelector('.clause-input');
+        const text = input.value.trim();
+        clauses.push({
+            clause: text,
+            source: input.dataset.source,
+            role: row.querySelector('.clause-role').value,
+        });
+    }
+    return clauses;
+}
+
+async function processFormalize() {
+    const inputField = document.getElementById('input');
+    const outputField = document.getElementById('output');
+    const btn = document.getElementById('formalizeBtn');
+    const editor = document.getElementById('clauseEditor');
+    const inputText = inputField.value;
+
+    if (!inputText) return;
+
+    btn.classList.add('processing');
+    btn.disabled = true;
+    outputField.textContent = "";
+    showProofTree("");
+    try {
+        const response = await formalizeProblem(inputText, document.getElementById('translation').value);
+        if (response.error) {
+            outputField.textContent = response.error;
+            return;
+        }
+        document.getElementById('clauseList').innerHTML = "";
+        response.clauses.forEach(addClauseRow);
+        showGlossary(response.glossary || []);
+        editor.classList.add('visible');
+    } catch (error) {
+        outputField.textContent = "Error: " + error;
+    } finally {
+        btn.classList.remove('processing');
+        btn.disabled = false;
+    }
+}
+
+// Словарь символов последней формализации: показывается под клаузами
+// и передаётся в объяснение
+let currentGlossary = [];
+
+function showGlossary(glossary) {
+    currentGlossary = glossary;
+    document.getElementById('glossary').textContent =
+        glossary.map(g => `${g.name}/${g.arity} — ${g.description}`).join("\n");
+}
+
+// Варианты голосования: кнопка открывает формализацию в редакторе клауз
+function showVariants(variants) {
+    const container = document.getElementById('variants');
+    container.innerHTML = "";
+    variants.forEach((variant, i) => {
+        if (variant.error) return;
+        const btn = document.createElement('button');
+        btn.className = 'neu-btn neu-btn-small' + (variant.majority ? ' majority' : '');
+        btn.textContent = `ВАРИАНТ ${i + 1}: ${VERDICT_LABELS[variant.verdict] || variant.verdict}`;
+        btn.onclick = () => {
+            document.getElementById('clauseList').innerHTML = "";
+            variant.clauses.forEach(addClauseRow);
+            showGlossary(variant.glossary || []);
+            document.getElementById('clauseEditor').classList.add('visible');
+        };
+        container.appendChild(btn);
+    });
+}
+
+// Ошибки проверки клауз выводятся под соответствующими строками редактора
+function showClauseErrors(errors) {
+    document.querySelectorAll('#clauseList .clause-error').forEach(el => el.remove());
+    const rows = filledClauseRows();
+    for (const e of errors || []) {
+        const row = rows[e.line - 1];
+        if (!row) continue;
+        const note = document.createElement('div');
+        note.className = 'clause-error';
+        note.textContent = e.message;
+        row.appendChild(note);
+    }
+}
+
+async function processProve() {
+    const outputField = document.getElementById('output');
+    const btn = document.getElementById('proveBtn');
+
+    btn.classList.add('processing');
+    btn.disabled = true;
+    outputField.textContent = "";
+    showProofTree("");
+    try {
+        const response = await proveClauses(document.getElementById('input').value, collectClauses(), currentGlossary, solveOptions());
+        showClauseErrors(response.errors);
+        showProofTree(response.svg);
+        await typeWriter(response.text, 'output', 20);
+    } catch (error) {
+        outputField.textContent = "Error: " + error;
+    } finally {
+        btn.classList.remove('processing');
+        btn.disabled = false;
+    }
+}
+
+// ==========================================
+// Настройки
+// ==========================================
+
+function getSettings() {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        getSettingsAsync(callbackId);
+    });
+}
+
+function saveSettings(settings) {
+    return new Promise((resolve) => {
+        const callbackId = 'cb_' + (++window._callbackCounter);
+        window._pendingCallbacks[callbackId] = resolve;
+        saveSettingsAsync(settings, callbackId);
+    });
+}
+
+async function openSettings() {
+    const s = await getSettings();
+    document.getElementById('setProvider').value = s.provider;
+    document.getElementById('setBaseURL').value = s.base_url;
+    document.getElementById('setModel').value = s.model;
+    document.getElementById('setAPIKey').value = "";
+    document.getElementById('setAPIKey').placeholder = s.key_stored ? "сохранён" : "из OPENAI_API_KEY";
+    document.getElementById('setPassphrase').value = "";
+    document.getElementById('passphraseRow').style.display = s.keyring ? "none" : "";
+    document.getElementById('keyStatus').textContent = keyStatus(s);
+    document.getElementById('setForgetKey').checked = false;
+    document.getElementById('forgetKeyRow').style.display = s.key_stored ? "" : "none";
+    document.getElementById('setParsingTemp').value = s.parsing_temperature;
+    document.getElementById('setExplanationTemp').value = s.explanation_temperature;
+    document.getElementById('setMaxIterations').value = s.max_iterations;
+    document.getElementById('setPort').value = s.port;
+    document.getElementById('settingsStatus').textContent = "";
+    document.getElementById('settingsPanel').classList.add('visible');
+}
+
+// keyStatus описывает, где хранится ключ API
+function keyStatus(s) {
+    if (s.locked) return "🔒 Ключ зашифрован: введите пароль, чтобы его расшифровать.";
+    if (!s.key_stored) return "";
+    return s.keyring ? "🔑 Ключ хранится в связке ключей системы." : "🔑 Ключ хранится в зашифрованном файле.";
+}
+
+function closeSettings() {
+    document.getElementById('settingsPanel').classList.remove('visible');
+}
+
+async function processSaveSettings() {
+    const btn = document.getElementById('saveSettingsBtn');
+    const status = document.getElementById('settingsStatus');
+
+    btn.disabled = true;
+    try {
+        const result = await saveSettings({
+            provider: document.getElementById('setProvider').value,
+            base_url: document.getElementById('setBaseURL').value,
+            model: document.getElementById('setModel').value,
+            api_key: document.getElementById('setAPIKey').value,
+            passphrase: document.getElementById('setPassphrase').value,
+            forget_key: document.getElementById('setForgetKey').checked,
+            parsing_temperature: parseFloat(document.getElementById('setParsingTemp').value),
+            explanation_temperature: parseFloat(document.getElementById('setExplanationTemp').value),
+            max_iterations: parseInt(document.getElementById('setMaxIterations').value, 10),
+            port: parseInt(document.getElementById('setPort').value, 10),
+        });
+        status.textContent = result.error || "";
+        if (result.ok && !result.error) closeSettings();
+    } finally {
+        btn.disabled = false;
+    }
 }
\ No newline at end of file
@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"neurosolver/resolution"
+	"strings"
+
+	webview "github.com/webview/webview_go"
+)
+
+// ExportProblemHandler возвращает функцию-обработчик для экспорта последней
+// формализованной задачи ("smtlib", "tptp", "dimacs", "json") или её
+// доказательства ("dot", "mermaid", "latex", "result-json").
+func ExportProblemHandler(w webview.WebView) func(format string, callbackId string) {
+	return func(format string, callbackId string) {
+		go func() {
+			cacheMu.Lock()
+			engine, proof, text := cacheProblem, cacheProof, cacheProblemText
+			cacheMu.Unlock()
+
+			result, err := exportProblem(engine, proof, text, format)
+			if err != nil {
+				result = "❌ Ошибка: " + err.Error()
+			}
+			resolveCallback(w, callbackId, result)
+		}()
+	}
+}
+
+// exportProblem записывает задачу engine (text — её исходный текст) или
+// доказательство proof в формате format.
+func exportProblem(engine *resolution.ResolutionEngine, proof resolution.ProofResult, text, format string) (string, error) {
+	if engine == nil {
+		return "", errors.New("нет формализованной задачи — сначала решите задачу")
+	}
+
+	var buf strings.Builder
+	var err error
+	switch format {
+	case "smtlib":
+		err = engine.WriteSMTLIB(&buf)
+	case "tptp":
+		err = engine.WriteTPTP(&buf)
+	case "dimacs":
+		_, err = engine.WriteDIMACS(&buf)
+	case "json":
+		err = resolution.WriteDocument(&buf, engine.ProblemDocument(text))
+	case "result-json":
+		err = resolution.WriteDocument(&buf, resolution.NewResultDocument(proof))
+	case "dot", "mermaid", "latex":
+		if !proof.Success {
+			return "", errors.New("доказательство не найдено — строить дерево не из чего")
+		}
+		switch format {
+		case "dot":
+			return resolution.FormatDOT(proof), nil
+		case "mermaid":
+			return resolution.FormatMermaid(proof), nil
+		}
+		return resolution.FormatLaTeX(proof), nil
+	default:
+		return "", fmt.Errorf("неизвестный формат экспорта: %s", format)
+	}
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
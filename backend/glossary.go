@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"fmt"
+	"neurosolver/llmcore"
+	"neurosolver/resolution"
+	"strings"
+)
+
+// validateGlossary сверяет словарь символов от LLM с клаузами: каждый
+// предикат, функция и константа описаны ровно один раз, вид и арность
+// совпадают, лишних записей нет.
+func validateGlossary(glossary []llmcore.GlossaryEntry, clauses []*resolution.Clause) []string {
+	var problems []string
+	entries := make(map[string]llmcore.GlossaryEntry)
+	for _, g := range glossary {
+		if _, ok := entries[g.Name]; ok {
+			problems = append(problems, fmt.Sprintf("символ %s описан в glossary несколько раз", g.Name))
+			continue
+		}
+		entries[g.Name] = g
+		if strings.TrimSpace(g.Description) == "" {
+			problems = append(problems, fmt.Sprintf("у символа %s в glossary пустое описание", g.Name))
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, sym := range resolution.CollectSymbols(clauses) {
+		used[sym.Name] = true
+		g, ok := entries[sym.Name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("символ %s (%s, аргументов: %d) не описан в glossary", sym.Name, sym.Kind, sym.Arity))
+		case g.Kind != sym.Kind || g.Arity != sym.Arity:
+			problems = append(problems, fmt.Sprintf("в glossary %s — %s с %d аргументами, а в клаузах — %s с %d",
+				sym.Name, g.Kind, g.Arity, sym.Kind, sym.Arity))
+		}
+	}
+	for _, g := range glossary {
+		if !used[g.Name] {
+			problems = append(problems, fmt.Sprintf("в glossary описан символ %s, которого нет в клаузах", g.Name))
+		}
+	}
+	return problems
+}
+
+// formatGlossary перечисляет символы с описаниями: «• Любит/2 — x любит y».
+func formatGlossary(glossary []llmcore.GlossaryEntry) string {
+	lines := make([]string, len(glossary))
+	for i, g := range glossary {
+		lines[i] = fmt.Sprintf("• %s/%d — %s", g.Name, g.Arity, g.Description)
+	}
+	return strings.Join(lines, "\n")
+}
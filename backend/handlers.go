@@ -1,106 +1,400 @@
 package backend
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"neurosolver/llmcore"
 	"neurosolver/resolution"
-	"time"
+	"strings"
+	"sync"
 
 	webview "github.com/webview/webview_go"
 )
 
+// Кэш последнего решения. Обработчики работают в отдельных горутинах,
+// поэтому переменные кэша читаются и пишутся только под cacheMu.
 var (
+	cacheMu sync.Mutex
+
 	cacheText        string
 	cacheShortLog    string
 	cacheExplanation string
+	cachePolish      bool
+	cacheTranslation string
+	cacheFormalized  string
+	cacheGlossary    string
+	cacheCore        string
+	cacheWarning     string
+	cacheProofSVG    string
+
+	// Последняя формализованная задача и её доказательство (для экспорта)
+	cacheProblem     *resolution.ResolutionEngine
+	cacheProblemText string
+	cacheProof       resolution.ProofResult
 )
 
+// SolveOptions — настройки решения, передаваемые из UI
+type SolveOptions struct {
+	ShowLog bool `json:"showLog"` // добавить лог движка к объяснению
+	Polish  bool `json:"polish"`  // переписать шаблонное объяснение с помощью LLM
+
+	// Обратный перевод клауз: TranslationOff, TranslationTemplate или TranslationLLM
+	Translation string `json:"translation"`
+
+	// Число формализаций для голосования; 0 и 1 — одна формализация
+	Samples int `json:"samples"`
+}
+
+// SolveResult — ответ solveProblemAsync: текст и дерево доказательства в SVG
+type SolveResult struct {
+	Text   string       `json:"text"`
+	SVG    string       `json:"svg,omitempty"`
+	Errors []InputError `json:"errors,omitempty"` // ошибки ручного ввода по строкам или клауз редактора
+
+	// Формализации, участвовавшие в голосовании (SolveOptions.Samples > 1)
+	Variants []Variant `json:"variants,omitempty"`
+}
+
+// InputError — ошибка в строке ручного ввода или в клаузе редактора
+type InputError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// resolveCallback передаёт результат в JS-обработчик window._resolveCallback
+func resolveCallback(w webview.WebView, callbackId string, result interface{}) {
+	w.Dispatch(func() {
+		escaped, _ := json.Marshal(result)
+		w.Eval(fmt.Sprintf("window._resolveCallback('%s', %s)", callbackId, escaped))
+	})
+}
+
+// formatResult собирает итоговый текст для UI; warning — предупреждение
+// о неполной проверке условий (может быть пустым)
+func formatResult(formalized, glossary, shortLog, explanation, core, warning string, showLog bool) string {
+	result := explanation
+	if showLog {
+		result = "=== Лог движка резолюций ===\n" + shortLog + "\n\n=== Объяснение ===\n" + explanation
+	}
+	if glossary != "" {
+		result = "=== Словарь ===\n" + glossary + "\n\n" + result
+	}
+	if formalized != "" {
+		result = "=== Формализация ===\n" + formalized + "\n\n" + result
+	}
+	if core != "" {
+		result += "\n\n=== Использованные утверждения ===\n" + core
+	}
+	if warning != "" {
+		result += "\n\n" + warning
+	}
+	return result
+}
+
+// formatCore сопоставляет клаузы минимального ядра с предложениями задачи.
+// Клаузы отрицания цели пропускаются: нужны только использованные посылки.
+func formatCore(core []*resolution.Clause) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for _, c := range core {
+		if c.IsGoal() {
+			continue
+		}
+		line := c.Source
+		if line == "" {
+			line = c.String()
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		lines = append(lines, "• "+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // SolveProblemHandler возвращает функцию-обработчик для решения логических задач
-func SolveProblemHandler(w webview.WebView) func(text string, showLog bool, callbackId string) {
-	return func(text string, showLog bool, callbackId string) {
+// за один шаг: формализация через LLM и сразу доказательство
+func SolveProblemHandler(w webview.WebView) func(text string, opts SolveOptions, callbackId string) {
+	return func(text string, opts SolveOptions, callbackId string) {
 		// Запускаем в отдельной горутине
 		go func() {
-			// Вспомогательная функция для отправки ошибки в UI
-			sendError := func(errMsg string) {
-				w.Dispatch(func() {
-					escaped, _ := json.Marshal("❌ Ошибка: " + errMsg)
-					w.Eval(fmt.Sprintf("window._resolveCallback('%s', %s)", callbackId, escaped))
-				})
+			// Голосование не кэшируется: каждая выборка формализаций своя
+			if opts.Samples > 1 {
+				setCacheText("")
+				resolveCallback(w, callbackId, voteSolve(context.Background(), text, opts))
+				return
 			}
 
 			// Проверяем кэш - если текст тот же, просто переформатируем результат
-			if cacheText == text && cacheShortLog != "" && cacheExplanation != "" {
+			if cached, ok := cachedResult(text, opts); ok {
 				fmt.Println("CACHED VALUE!!!")
-				var finalResult string
-				if showLog {
-					finalResult = "=== Лог движка резолюций ===\n" + cacheShortLog + "\n\n=== Объяснение ===\n" + cacheExplanation
-				} else {
-					finalResult = cacheExplanation
-				}
-
-				w.Dispatch(func() {
-					escaped, _ := json.Marshal(finalResult)
-					w.Eval(fmt.Sprintf("window._resolveCallback('%s', %s)", callbackId, escaped))
-				})
+				resolveCallback(w, callbackId, cached)
 				return
 			}
 
+			ctx := context.Background()
+
 			// Шаг 1: Парсинг текста через LLM
-			result, err := llmcore.LLMQuery(llmcore.ParsingPrompt, text, 0.2)
-			fmt.Println("LLM Parsed:", result)
+			formalization, err := formalize(ctx, text, currentSettings().ParsingTemperature)
 			if err != nil {
-				sendError(err.Error())
+				resolveCallback(w, callbackId, SolveResult{Text: "❌ Ошибка: " + err.Error()})
 				return
 			}
 
-			parsedResult, err := llmcore.ParseStringList(result)
-			fmt.Println("After parse json:", parsedResult)
+			// Шаги 2 и 3: доказательство и объяснение
+			result, err := prove(ctx, text, formalization.Clauses, formalization.Glossary, opts)
 			if err != nil {
-				sendError("Не удалось распознать логические формулы: " + err.Error())
+				resolveCallback(w, callbackId, SolveResult{Text: "❌ Ошибка: " + err.Error()})
 				return
 			}
+			setCacheText(text)
+			resolveCallback(w, callbackId, result)
+		}()
+	}
+}
 
-			if len(parsedResult) == 0 {
-				sendError("LLM вернул пустой результат. Попробуйте переформулировать задачу.")
-				return
-			}
+// cachedResult возвращает кэшированное решение text, если оно получено
+// с теми же настройками объяснения и пересказа.
+func cachedResult(text string, opts SolveOptions) (SolveResult, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if cacheText != text || cachePolish != opts.Polish || cacheTranslation != opts.Translation ||
+		cacheShortLog == "" || cacheExplanation == "" {
+		return SolveResult{}, false
+	}
+	finalResult := formatResult(cacheFormalized, cacheGlossary, cacheShortLog, cacheExplanation, cacheCore, cacheWarning, opts.ShowLog)
+	return SolveResult{Text: finalResult, SVG: cacheProofSVG}, true
+}
 
-			// Шаг 2: Запуск движка резолюций
-			engine := resolution.NewResolutionEngine()
-			engine.ParseInput(parsedResult)
-			proofResult := engine.Prove()
-			shortLog := proofResult.ShortLog
-			fmt.Println("SHORT LOG:", shortLog)
-
-			// Шаг 3: Генерация объяснения через LLM
-			time.Sleep(5 * time.Second) // --- IGNORE ---
-			explanation, err := llmcore.LLMQuery(llmcore.ExplanationPrompt, shortLog, 1)
-			fmt.Println("EXPLANATION:", explanation)
-			if err != nil {
-				// Если не удалось получить объяснение, показываем хотя бы лог
-				explanation = "(Не удалось сгенерировать объяснение: " + err.Error() + ")"
-			}
+// setCacheText отмечает, для какого текста задачи сохранён кэш; пустая
+// строка отключает кэш (клаузы вводились или правились вручную).
+func setCacheText(text string) {
+	cacheMu.Lock()
+	cacheText = text
+	cacheMu.Unlock()
+}
 
-			// Сохраняем в кэш
-			cacheText = text
-			cacheShortLog = shortLog
-			cacheExplanation = explanation
-
-			// Формируем результат в зависимости от флага
-			var finalResult string
-			if showLog {
-				finalResult = "=== Лог движка резолюций ===\n" + shortLog + "\n\n=== Объяснение ===\n" + explanation
-			} else {
-				finalResult = explanation
-			}
+// maxFormalizeAttempts — сколько раз LLM может исправить формализацию
+// по списку ошибок, включая первую попытку.
+const maxFormalizeAttempts = 3
 
-			// Возвращаем результат через JS callback
-			w.Dispatch(func() {
-				// Экранируем кавычки и переносы строк в результате
-				escaped, _ := json.Marshal(finalResult)
-				w.Eval(fmt.Sprintf("window._resolveCallback('%s', %s)", callbackId, escaped))
-			})
-		}()
+// formalize переводит текст задачи в клаузы и словарь символов через LLM
+// (со схемой ответа, если провайдер её поддерживает). Ответ проверяется
+// (JSON, синтаксис клауз, имена, арность, отрицание цели); ошибки
+// отправляются модели следующим сообщением, пока она их не исправит или
+// не кончатся попытки. Если ошибки остались и после последней попытки,
+// возвращается ошибка со списком проблем — такие клаузы не доказываются.
+func formalize(ctx context.Context, text string, temperature float64) (llmcore.Formalization, error) {
+	req := llmcore.Request{
+		System:      llmcore.ParsingPrompt,
+		Messages:    []llmcore.Message{{Role: llmcore.RoleUser, Content: text}},
+		Temperature: temperature,
+		Schema:      llmcore.FormalizationSchema,
 	}
+	var problems []string
+	for attempt := 1; attempt <= maxFormalizeAttempts; attempt++ {
+		result, err := llmcore.LLMChat(ctx, req)
+		fmt.Println("LLM Parsed:", result)
+		if err != nil {
+			return llmcore.Formalization{}, err
+		}
+
+		var parsedResult llmcore.Formalization
+		parsedResult, problems = checkFormalization(result)
+		fmt.Printf("FORMALIZE ATTEMPT %d/%d: %d ошибок\n", attempt, maxFormalizeAttempts, len(problems))
+		if len(problems) == 0 {
+			return parsedResult, nil
+		}
+		fmt.Println("FORMALIZATION ERRORS:\n" + strings.Join(problems, "\n"))
+
+		req.Messages = append(req.Messages,
+			llmcore.Message{Role: llmcore.RoleAssistant, Content: result},
+			llmcore.Message{Role: llmcore.RoleUser, Content: fmt.Sprintf(llmcore.RepairPrompt, "- "+strings.Join(problems, "\n- "))})
+	}
+
+	return llmcore.Formalization{}, errors.New("Не удалось распознать логические формулы: " + strings.Join(problems, "; "))
+}
+
+// checkFormalization разбирает ответ LLM и возвращает формализацию (без
+// клауз, если JSON не разобран) и список ошибок для повторного запроса.
+func checkFormalization(result string) (llmcore.Formalization, []string) {
+	formalization, err := llmcore.ParseFormalization(result)
+	if err != nil {
+		return llmcore.Formalization{}, []string{"ответ не является JSON-объектом {\"clauses\": [...], \"glossary\": [...]}: " + err.Error()}
+	}
+	parsed := formalization.Clauses
+	if len(parsed) == 0 {
+		return llmcore.Formalization{}, []string{"массив клауз пуст"}
+	}
+
+	var problems []string
+	inputs := make([]resolution.InputClause, len(parsed))
+	for i, pc := range parsed {
+		role, err := resolution.ParseRole(pc.Role)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("клауза %d «%s»: %v", i+1, pc.Clause, err))
+		}
+		inputs[i] = resolution.InputClause{Text: pc.Clause, Source: pc.Source, Role: role}
+	}
+	syntaxErrors := resolution.ValidateInput(inputs)
+	for _, e := range syntaxErrors {
+		problems = append(problems, e.Error())
+	}
+	// Словарь сверяем только с синтаксически верными клаузами. Ответ без
+	// словаря (модели без структурированного вывода) принимается как есть:
+	// иначе повторные запросы отвергли бы верную формализацию
+	if len(syntaxErrors) == 0 && formalization.Glossary != nil {
+		clauses := resolution.NewResolutionEngine().AddInput(inputs)
+		problems = append(problems, validateGlossary(formalization.Glossary, clauses)...)
+	}
+	return formalization, problems
+}
+
+// newProblem создаёт движок с клаузами задачи
+func newProblem(parsed []llmcore.ParsedClause) (*resolution.ResolutionEngine, []*resolution.Clause, error) {
+	inputs := make([]resolution.InputClause, len(parsed))
+	for i, pc := range parsed {
+		role, err := resolution.ParseRole(pc.Role)
+		if err != nil {
+			return nil, nil, errors.New("Не удалось распознать логические формулы: " + err.Error())
+		}
+		inputs[i] = resolution.InputClause{Text: pc.Clause, Source: pc.Source, Role: role}
+	}
+	engine := resolution.NewResolutionEngine()
+	engine.MaxIterations = currentSettings().MaxIterations
+	return engine, engine.AddInput(inputs), nil
+}
+
+// prove запускает движок резолюций на клаузах задачи и объясняет результат.
+// Результат сохраняется в кэш (кроме cacheText — его выставляет вызывающий).
+func prove(ctx context.Context, text string, parsed []llmcore.ParsedClause, glossary []llmcore.GlossaryEntry, opts SolveOptions) (SolveResult, error) {
+	engine, clauses, err := newProblem(parsed)
+	if err != nil {
+		return SolveResult{}, err
+	}
+	return proveEngine(ctx, text, engine, clauses, glossary, opts), nil
+}
+
+// proofRun — проверка посылок на противоречивость и доказательство цели
+type proofRun struct {
+	engine      *resolution.ResolutionEngine
+	clauses     []*resolution.Clause
+	consistency resolution.ConsistencyResult
+	proof       resolution.ProofResult // пуст, если посылки противоречивы
+}
+
+// runProof проверяет посылки и, если они непротиворечивы, доказывает цель.
+func runProof(engine *resolution.ResolutionEngine, clauses []*resolution.Clause) proofRun {
+	run := proofRun{engine: engine, clauses: clauses, consistency: engine.CheckConsistency()}
+	if run.consistency.Consistent {
+		run.proof = engine.Prove()
+	}
+	return run
+}
+
+// proveEngine — общая часть prove и ручного ввода: движок уже заполнен
+// клаузами задачи. glossary — словарь символов от LLM (при ручном вводе пуст).
+func proveEngine(ctx context.Context, text string, engine *resolution.ResolutionEngine, clauses []*resolution.Clause, glossary []llmcore.GlossaryEntry, opts SolveOptions) SolveResult {
+	return reportProof(ctx, text, runProof(engine, clauses), glossary, opts)
+}
+
+// reportProof объясняет готовый результат доказательства и сохраняет его в кэш.
+func reportProof(ctx context.Context, text string, run proofRun, glossary []llmcore.GlossaryEntry, opts SolveOptions) SolveResult {
+	engine, clauses := run.engine, run.clauses
+	cacheMu.Lock()
+	cacheProblem = engine
+	cacheProblemText = text
+	cacheProof = resolution.ProofResult{}
+	// Объяснение прежней задачи не должно попасть в кэш новой
+	cacheShortLog, cacheExplanation = "", ""
+	cacheMu.Unlock()
+
+	// Пересказ клауз — чтобы пользователь мог проверить формализацию
+	formalized := ""
+	if opts.Translation != TranslationOff {
+		formalized = formatFormalization(clauses, backTranslate(ctx, clauses, opts.Translation))
+	}
+
+	// Смысл символов, придуманных LLM
+	glossaryText := formatGlossary(glossary)
+
+	// Противоречивые посылки «доказывают» что угодно — проверяем их до цели
+	consistency := run.consistency
+	if !consistency.Consistent {
+		fmt.Println("INCONSISTENT PREMISES:", consistency.Log)
+		report := "⚠️ Условия задачи противоречат друг другу, поэтому из них можно «доказать» любое утверждение. " +
+			"Проверьте формулировку.\n\n=== Конфликтующие утверждения ===\n" + formatCore(consistency.Conflict)
+		if opts.ShowLog {
+			report += "\n\n=== Лог движка резолюций ===\n" + consistency.Log
+		}
+		if glossaryText != "" {
+			report = "=== Словарь ===\n" + glossaryText + "\n\n" + report
+		}
+		if formalized != "" {
+			report = "=== Формализация ===\n" + formalized + "\n\n" + report
+		}
+		return SolveResult{Text: report}
+	}
+
+	// Проверка условий ограничена лимитом: без противоречия в пределах
+	// лимита условия не проверены до конца
+	warning := ""
+	if !consistency.Complete {
+		warning = "⚠️ Проверка непротиворечивости условий остановлена по лимиту: противоречие не найдено, " +
+			"но и не исключено. Если условия противоречивы, из них «доказывается» что угодно."
+	}
+
+	proofResult := run.proof
+	shortLog := proofResult.ShortLog
+	fmt.Println("SHORT LOG:", shortLog)
+	cacheMu.Lock()
+	cacheProof = proofResult
+	cacheMu.Unlock()
+
+	// Минимальный набор утверждений, без которых доказательство невозможно
+	core := formatCore(engine.MinimalCore(proofResult))
+
+	// Объяснение по шаблонам, по желанию — литературная правка через LLM
+	explanation := resolution.ExplainProof(proofResult)
+	if opts.Polish {
+		// Неудача объясняется отдельным промптом по разбору насыщенного
+		// множества: промпт успешного опровержения выдумал бы доказательство
+		prompt, request := llmcore.ExplanationPrompt, shortLog
+		if !proofResult.Success {
+			prompt, request = llmcore.FailureExplanationPrompt, resolution.FormatFailure(proofResult)
+		}
+		if glossaryText != "" {
+			request += "\n\nСЛОВАРЬ СИМВОЛОВ:\n" + glossaryText
+		}
+		request += "\n\nЧЕРНОВИК ОБЪЯСНЕНИЯ:\n" + explanation
+		polished, err := llmcore.LLMQuery(ctx, prompt, request, currentSettings().ExplanationTemperature)
+		if err != nil {
+			// Шаблонное объяснение остаётся, если LLM недоступна
+			fmt.Println("EXPLANATION POLISH FAILED:", err)
+		} else {
+			explanation = polished
+		}
+	}
+	fmt.Println("EXPLANATION:", explanation)
+
+	// Сохраняем в кэш
+	svg := resolution.RenderSVG(proofResult)
+	cacheMu.Lock()
+	cacheShortLog = shortLog
+	cacheExplanation = explanation
+	cachePolish = opts.Polish
+	cacheTranslation = opts.Translation
+	cacheFormalized = formalized
+	cacheGlossary = glossaryText
+	cacheCore = core
+	cacheWarning = warning
+	cacheProofSVG = svg
+	cacheMu.Unlock()
+
+	// Формируем результат в зависимости от флага
+	finalResult := formatResult(formalized, glossaryText, shortLog, explanation, core, warning, opts.ShowLog)
+	return SolveResult{Text: finalResult, SVG: svg}
 }
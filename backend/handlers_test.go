@@ -0,0 +1,24 @@
+package backend
+
+import "testing"
+
+func TestCheckFormalizationWithoutGlossary(t *testing.T) {
+	// Модели без структурированного вывода отвечают массивом клауз без словаря
+	result := `[
+		{"clause": "¬Человек(x) ∨ Смертен(x)", "source": "Все люди смертны.", "role": "axiom"},
+		{"clause": "Человек(Сократ)", "source": "Сократ — человек.", "role": "hypothesis"},
+		{"clause": "¬Смертен(Сократ)", "source": "Сократ смертен.", "role": "negated_conjecture"}
+	]`
+	f, problems := checkFormalization(result)
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if len(f.Clauses) != 3 || f.Glossary != nil {
+		t.Fatalf("unexpected formalization: %+v", f)
+	}
+
+	// Пустой словарь в объекте по-прежнему проверяется
+	if _, problems := checkFormalization(`{"clauses": [{"clause": "¬Смертен(Сократ)", "source": "", "role": "negated_conjecture"}], "glossary": []}`); len(problems) == 0 {
+		t.Fatal("expected glossary problems for an empty glossary")
+	}
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"neurosolver/resolution"
+
+	webview "github.com/webview/webview_go"
+)
+
+// ManualSolveHandler возвращает обработчик ручного режима: клаузы и формулы
+// из текстового поля разбираются без обращения к LLM. Ошибки разбора
+// возвращаются по строкам, чтобы UI показал их рядом с вводом.
+func ManualSolveHandler(w webview.WebView) func(text string, opts SolveOptions, callbackId string) {
+	return func(text string, opts SolveOptions, callbackId string) {
+		go func() {
+			engine := resolution.NewResolutionEngine()
+			engine.MaxIterations = currentSettings().MaxIterations
+			clauses, err := engine.ParseManual(text)
+
+			var parseErrors resolution.ParseErrors
+			if errors.As(err, &parseErrors) {
+				result := SolveResult{Text: "❌ Ошибки ввода:\n" + err.Error()}
+				for _, le := range parseErrors {
+					result.Errors = append(result.Errors, InputError{Line: le.Line, Message: le.Msg})
+				}
+				resolveCallback(w, callbackId, result)
+				return
+			}
+			if errors.Is(err, resolution.ErrNoGoal) {
+				resolveCallback(w, callbackId, SolveResult{Text: "❌ Ошибка: " + err.Error() + ". Отметьте цель строкой «⊢ утверждение»"})
+				return
+			}
+			if len(clauses) == 0 {
+				resolveCallback(w, callbackId, SolveResult{Text: "❌ Ошибка: введите хотя бы одну клаузу или формулу"})
+				return
+			}
+
+			// Ручной ввод не кэшируется по тексту: разбор дешевле проверки кэша
+			setCacheText("")
+			resolveCallback(w, callbackId, proveEngine(context.Background(), text, engine, clauses, nil, opts))
+		}()
+	}
+}
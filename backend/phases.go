@@ -0,0 +1,116 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"neurosolver/llmcore"
+	"neurosolver/resolution"
+	"strings"
+
+	webview "github.com/webview/webview_go"
+)
+
+// FormalizedClause — клауза для редактирования в UI перед доказательством
+type FormalizedClause struct {
+	Clause      string `json:"clause"`
+	Source      string `json:"source"`
+	Role        string `json:"role"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// FormalizeResult — ответ formalizeAsync
+type FormalizeResult struct {
+	Clauses  []FormalizedClause      `json:"clauses"`
+	Glossary []llmcore.GlossaryEntry `json:"glossary,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// FormalizeHandler возвращает обработчик первого этапа: текст задачи
+// переводится в клаузы, которые пользователь может исправить перед
+// доказательством. translation — режим пересказа клауз.
+func FormalizeHandler(w webview.WebView) func(text string, translation string, callbackId string) {
+	return func(text string, translation string, callbackId string) {
+		go func() {
+			ctx := context.Background()
+			formalization, err := formalize(ctx, text, currentSettings().ParsingTemperature)
+			if err != nil {
+				resolveCallback(w, callbackId, FormalizeResult{Error: "❌ Ошибка: " + err.Error()})
+				return
+			}
+			parsed := formalization.Clauses
+
+			result := FormalizeResult{Clauses: make([]FormalizedClause, len(parsed)), Glossary: formalization.Glossary}
+			for i, pc := range parsed {
+				result.Clauses[i] = FormalizedClause{Clause: pc.Clause, Source: pc.Source, Role: pc.Role}
+			}
+
+			if translation != TranslationOff {
+				if _, clauses, err := newProblem(parsed); err != nil {
+					fmt.Println("BACK TRANSLATION SKIPPED:", err)
+				} else {
+					for i, t := range backTranslate(ctx, clauses, translation) {
+						result.Clauses[i].Translation = t
+					}
+				}
+			}
+			resolveCallback(w, callbackId, result)
+		}()
+	}
+}
+
+// ProveHandler возвращает обработчик второго этапа: доказательство на
+// клаузах, возможно исправленных пользователем. glossary — словарь символов
+// с первого этапа.
+func ProveHandler(w webview.WebView) func(text string, clauses []llmcore.ParsedClause, glossary []llmcore.GlossaryEntry, opts SolveOptions, callbackId string) {
+	return func(text string, clauses []llmcore.ParsedClause, glossary []llmcore.GlossaryEntry, opts SolveOptions, callbackId string) {
+		go func() {
+			if len(clauses) == 0 {
+				resolveCallback(w, callbackId, SolveResult{Text: "❌ Ошибка: список клауз пуст"})
+				return
+			}
+
+			// AddInput молча пропускает ошибочные литералы — проверяем
+			// исправленные клаузы до доказательства
+			if errs := validateClauses(clauses); len(errs) > 0 {
+				lines := make([]string, len(errs))
+				for i, e := range errs {
+					lines[i] = e.Message
+					if e.Line > 0 {
+						lines[i] = fmt.Sprintf("клауза %d: %s", e.Line, e.Message)
+					}
+				}
+				resolveCallback(w, callbackId, SolveResult{
+					Text:   "❌ Клаузы содержат ошибки, доказательство не запущено:\n" + strings.Join(lines, "\n"),
+					Errors: errs,
+				})
+				return
+			}
+
+			// Клаузы могли быть изменены — кэш по тексту задачи больше не годится
+			setCacheText("")
+			result, err := prove(context.Background(), text, clauses, glossary, opts)
+			if err != nil {
+				result = SolveResult{Text: "❌ Ошибка: " + err.Error()}
+			}
+			resolveCallback(w, callbackId, result)
+		}()
+	}
+}
+
+// validateClauses проверяет клаузы из редактора так же строго, как ответ
+// LLM. Line в ошибке — номер клаузы в списке, 0 — ошибка задачи в целом.
+func validateClauses(clauses []llmcore.ParsedClause) []InputError {
+	var errs []InputError
+	inputs := make([]resolution.InputClause, len(clauses))
+	for i, pc := range clauses {
+		role, err := resolution.ParseRole(pc.Role)
+		if err != nil {
+			errs = append(errs, InputError{Line: i + 1, Message: err.Error()})
+		}
+		inputs[i] = resolution.InputClause{Text: pc.Clause, Source: pc.Source, Role: role}
+	}
+	for _, e := range resolution.ValidateInput(inputs) {
+		errs = append(errs, InputError{Line: e.Index, Message: e.Msg})
+	}
+	return errs
+}
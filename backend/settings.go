@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"neurosolver/config"
+	"neurosolver/keystore"
+	"neurosolver/llmcore"
+	"strings"
+	"sync"
+
+	webview "github.com/webview/webview_go"
+)
+
+var (
+	settingsMu sync.RWMutex
+	settings   = config.Default()
+)
+
+func currentSettings() config.Config {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// ApplySettings проверяет настройки, делает их текущими и перенастраивает
+// провайдера LLM. Пустые поля провайдера берутся из окружения, ключ —
+// из OPENAI_API_KEY или хранилища ключей. Если
+// провайдер не создан (например, нет ключа), остальные настройки всё равно
+// действуют, а ошибка возвращается.
+func ApplySettings(cfg config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	settingsMu.Lock()
+	settings = cfg
+	settingsMu.Unlock()
+
+	provider := llmcore.ConfigFromEnv()
+	if cfg.Provider != "" {
+		provider.Kind = cfg.Provider
+	}
+	if cfg.BaseURL != "" {
+		provider.BaseURL = cfg.BaseURL
+	}
+	if cfg.Model != "" {
+		provider.Model = cfg.Model
+	}
+	if err := llmcore.Configure(provider); err != nil {
+		if errors.Is(err, llmcore.ErrAPIKeyMissing) && keystore.Locked() {
+			err = keystore.ErrLocked
+		}
+		return fmt.Errorf("LLM не настроена: %w", err)
+	}
+	return nil
+}
+
+// SaveSettingsResult — ответ saveSettingsAsync
+type SaveSettingsResult struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"` // при OK — предупреждение
+}
+
+// SettingsView — настройки для UI вместе с состоянием хранилища ключа.
+// Сам ключ в UI не передаётся.
+type SettingsView struct {
+	config.Config
+	KeyStored bool `json:"key_stored"` // ключ сохранён (в связке ключей или файле)
+	Keyring   bool `json:"keyring"`    // связка ключей ОС доступна
+	Locked    bool `json:"locked"`     // ключ в файле ждёт пароля
+}
+
+// SettingsForm — настройки из UI. Непустой APIKey сохраняется в хранилище
+// ключей; Passphrase шифрует его, если связки ключей нет, или расшифровывает
+// ранее сохранённый ключ. Пустой APIKey оставляет ключ как есть, а ForgetKey
+// удаляет сохранённый ключ.
+type SettingsForm struct {
+	config.Config
+	APIKey     string `json:"api_key"`
+	Passphrase string `json:"passphrase"`
+	ForgetKey  bool   `json:"forget_key"`
+}
+
+// GetSettingsHandler возвращает обработчик, отдающий текущие настройки в UI
+func GetSettingsHandler(w webview.WebView) func(callbackId string) {
+	return func(callbackId string) {
+		go func() {
+			locked := keystore.Locked()
+			resolveCallback(w, callbackId, SettingsView{
+				Config:    currentSettings(),
+				KeyStored: locked || keystore.Get() != "",
+				Keyring:   keystore.KeyringAvailable(),
+				Locked:    locked,
+			})
+		}()
+	}
+}
+
+// SaveSettingsHandler возвращает обработчик сохранения настроек из UI.
+// Настройки проверяются до сохранения ключа, чтобы неверная форма ничего
+// не записала. Применяются сразу, без перезапуска (кроме порта).
+func SaveSettingsHandler(w webview.WebView) func(form SettingsForm, callbackId string) {
+	return func(form SettingsForm, callbackId string) {
+		go func() {
+			cfg := form.Config.Normalize()
+			if err := cfg.Validate(); err != nil {
+				resolveCallback(w, callbackId, SaveSettingsResult{Error: "❌ " + err.Error()})
+				return
+			}
+			if err := saveKey(form.APIKey, form.Passphrase, form.ForgetKey); err != nil {
+				resolveCallback(w, callbackId, SaveSettingsResult{Error: "❌ " + err.Error()})
+				return
+			}
+			if err := config.Save(cfg); err != nil {
+				resolveCallback(w, callbackId, SaveSettingsResult{Error: "❌ " + err.Error()})
+				return
+			}
+			if err := ApplySettings(cfg); err != nil {
+				resolveCallback(w, callbackId, SaveSettingsResult{OK: true, Error: "⚠️ Настройки сохранены. " + err.Error()})
+				return
+			}
+			resolveCallback(w, callbackId, SaveSettingsResult{OK: true})
+		}()
+	}
+}
+
+// saveKey сохраняет новый ключ, удаляет сохранённый (forget) или, если ключ
+// не введён, расшифровывает сохранённый паролем. Новый ключ важнее forget.
+func saveKey(key, passphrase string, forget bool) error {
+	switch key = strings.TrimSpace(key); {
+	case key != "":
+		return keystore.Save(key, passphrase)
+	case forget:
+		return keystore.Delete()
+	case passphrase != "" && keystore.Locked():
+		return keystore.Unlock(passphrase)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"neurosolver/llmcore"
+	"neurosolver/resolution"
+	"strings"
+)
+
+// Режимы обратного перевода клауз на естественный язык
+const (
+	TranslationOff      = ""
+	TranslationTemplate = "template"
+	TranslationLLM      = "llm"
+)
+
+// backTranslate пересказывает клаузы по-русски, чтобы пользователь мог
+// сверить формализацию с задачей. В режиме TranslationLLM при ошибке
+// запроса используется перевод по шаблонам.
+func backTranslate(ctx context.Context, clauses []*resolution.Clause, mode string) []string {
+	if mode == TranslationLLM {
+		translations, err := llmBackTranslate(ctx, clauses)
+		if err == nil {
+			return translations
+		}
+		fmt.Println("BACK TRANSLATION FAILED:", err)
+	}
+
+	translations := make([]string, len(clauses))
+	for i, c := range clauses {
+		translations[i] = resolution.DescribeClause(c)
+	}
+	return translations
+}
+
+func llmBackTranslate(ctx context.Context, clauses []*resolution.Clause) ([]string, error) {
+	texts := make([]string, len(clauses))
+	for i, c := range clauses {
+		texts[i] = c.String()
+	}
+	request, _ := json.Marshal(texts)
+
+	result, err := llmcore.LLMQuery(ctx, llmcore.BackTranslationPrompt, string(request), currentSettings().ParsingTemperature)
+	if err != nil {
+		return nil, err
+	}
+	translations, err := llmcore.ParseStringList(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(translations) != len(clauses) {
+		return nil, fmt.Errorf("ожидалось %d предложений, получено %d", len(clauses), len(translations))
+	}
+	return translations, nil
+}
+
+// formatFormalization перечисляет клаузы задачи с их пересказом
+func formatFormalization(clauses []*resolution.Clause, translations []string) string {
+	var lines []string
+	for i, c := range clauses {
+		line := fmt.Sprintf("[%d] %s", c.ID, c.String())
+		if c.IsGoal() {
+			line += " (отрицание цели)"
+		}
+		line += "\n    → " + translations[i]
+		if c.Source != "" {
+			line += "\n    из: «" + c.Source + "»"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
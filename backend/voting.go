@@ -0,0 +1,224 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"neurosolver/llmcore"
+	"neurosolver/resolution"
+	"strings"
+	"sync"
+)
+
+// ==========================================
+// Голосование по нескольким формализациям
+// ==========================================
+
+const (
+	// maxSamples ограничивает число параллельных запросов к LLM
+	maxSamples = 7
+
+	// votingTemperature — нижняя граница температуры разбора при
+	// голосовании: при низкой температуре выборки почти не отличаются
+	votingTemperature = 0.7
+)
+
+// Вердикты формализации, кроме статусов resolution.Status*
+const (
+	verdictInconsistent = "inconsistent"
+	verdictError        = "error"
+)
+
+var verdictLabels = map[string]string{
+	resolution.StatusProved:    "доказано",
+	resolution.StatusNotProved: "не доказано",
+	resolution.StatusTimeout:   "лимит итераций",
+	verdictInconsistent:        "условия противоречивы",
+	verdictError:               "ошибка формализации",
+}
+
+// verdictOrder — вердикты от самого осторожного: при равенстве голосов
+// объясняется первый из них, а не «доказано»
+var verdictOrder = []string{
+	verdictInconsistent,
+	resolution.StatusNotProved,
+	resolution.StatusTimeout,
+	resolution.StatusProved,
+}
+
+// Variant — одна из формализаций голосования; UI позволяет открыть её
+// в редакторе клауз.
+type Variant struct {
+	Verdict  string                  `json:"verdict"`
+	Majority bool                    `json:"majority"` // вердикт большинства (при ничьей — ни у кого)
+	Clauses  []FormalizedClause      `json:"clauses,omitempty"`
+	Glossary []llmcore.GlossaryEntry `json:"glossary,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// sample — формализация и результат её доказательства
+type sample struct {
+	formalization llmcore.Formalization
+	clauses       []*resolution.Clause
+	run           proofRun
+	verdict       string
+	err           error
+}
+
+// tally подсчитывает голоса без ошибочных формализаций. verdict — вердикт
+// с наибольшим числом голосов; при ничьей (tie) — самый осторожный из
+// лидеров по verdictOrder. Пустой verdict — ни одного голоса.
+func tally(verdicts []string) (verdict string, votes int, tie bool) {
+	counts := make(map[string]int)
+	for _, v := range verdicts {
+		if v != verdictError {
+			counts[v]++
+		}
+	}
+	for _, v := range verdictOrder {
+		switch {
+		case counts[v] > votes:
+			verdict, votes, tie = v, counts[v], false
+		case counts[v] == votes && votes > 0:
+			tie = true
+		}
+	}
+	return verdict, votes, tie
+}
+
+// voteSolve получает opts.Samples формализаций параллельно, доказывает
+// каждую и выбирает вердикт большинства. Полное объяснение строится по уже
+// найденному результату первой формализации с этим вердиктом; при ничьей
+// отчёт сообщает, что согласия нет, и объясняет самый осторожный вердикт.
+// Отчёт о голосовании показывает, сколько формализаций согласны и какими
+// клаузами они различаются.
+func voteSolve(ctx context.Context, text string, opts SolveOptions) SolveResult {
+	k := min(opts.Samples, maxSamples)
+	temperature := max(currentSettings().ParsingTemperature, votingTemperature)
+
+	samples := make([]sample, k)
+	var wg sync.WaitGroup
+	for i := range samples {
+		wg.Add(1)
+		go func(s *sample) {
+			defer wg.Done()
+			s.formalization, s.err = formalize(ctx, text, temperature)
+			if s.err != nil {
+				s.verdict = verdictError
+				return
+			}
+			engine, clauses, err := newProblem(s.formalization.Clauses)
+			if err != nil {
+				s.verdict, s.err = verdictError, err
+				return
+			}
+			s.clauses = clauses
+			s.run = runProof(engine, clauses)
+			if !s.run.consistency.Consistent {
+				s.verdict = verdictInconsistent
+				return
+			}
+			s.verdict = resolution.NewResultDocument(s.run.proof).Status
+		}(&samples[i])
+	}
+	wg.Wait()
+
+	verdicts := make([]string, k)
+	for i, s := range samples {
+		verdicts[i] = s.verdict
+		fmt.Printf("VOTE %d/%d: %s\n", i+1, k, s.verdict)
+	}
+	majority, votes, tie := tally(verdicts)
+	if majority == "" {
+		return SolveResult{Text: "❌ Ошибка: " + samples[0].err.Error()}
+	}
+
+	var chosen *sample
+	variants := make([]Variant, k)
+	for i := range samples {
+		s := &samples[i]
+		variants[i] = Variant{Verdict: s.verdict, Majority: !tie && s.verdict == majority, Glossary: s.formalization.Glossary}
+		if s.err != nil {
+			variants[i].Error = s.err.Error()
+			continue
+		}
+		for _, pc := range s.formalization.Clauses {
+			variants[i].Clauses = append(variants[i].Clauses, FormalizedClause{Clause: pc.Clause, Source: pc.Source, Role: pc.Role})
+		}
+		if chosen == nil && s.verdict == majority {
+			chosen = s
+		}
+	}
+
+	result := reportProof(ctx, text, chosen.run, chosen.formalization.Glossary, opts)
+	result.Text = formatVoting(samples, majority, votes, tie) + "\n\n" + result.Text
+	result.Variants = variants
+	return result
+}
+
+// formatVoting описывает итог голосования и клаузы, которые есть не во
+// всех формализациях. При ничьей итог — «нет согласия».
+func formatVoting(samples []sample, majority string, agreed int, tie bool) string {
+	var b strings.Builder
+	if tie {
+		fmt.Fprintf(&b, "=== Голосование ===\nИтог: нет согласия — несколько вердиктов набрали по %d из %d голосов. "+
+			"Ниже объяснён самый осторожный из них: %s.", agreed, len(samples), verdictLabels[majority])
+	} else {
+		fmt.Fprintf(&b, "=== Голосование ===\nИтог: %s — согласны %d из %d формализаций.",
+			verdictLabels[majority], agreed, len(samples))
+	}
+
+	// Клаузы, общие для всех разобранных формализаций
+	common := make(map[string]int)
+	parsed := 0
+	for _, s := range samples {
+		if s.err != nil {
+			continue
+		}
+		parsed++
+		for text := range clauseSet(s.clauses) {
+			common[text]++
+		}
+	}
+
+	differ := false
+	for i, s := range samples {
+		if s.err != nil {
+			fmt.Fprintf(&b, "\nВариант %d — %s: %v", i+1, verdictLabels[s.verdict], s.err)
+			continue
+		}
+		var own []string
+		for _, c := range s.clauses {
+			if text := clauseText(c); common[text] < parsed {
+				own = append(own, "  • "+text)
+			}
+		}
+		fmt.Fprintf(&b, "\nВариант %d — %s", i+1, verdictLabels[s.verdict])
+		if len(own) > 0 {
+			differ = true
+			b.WriteString(", отличающиеся клаузы:\n" + strings.Join(own, "\n"))
+		}
+	}
+	if differ {
+		b.WriteString("\nЛюбой вариант можно открыть в редакторе клауз и доказать отдельно.")
+	} else if parsed > 1 {
+		b.WriteString("\nВсе разобранные формализации совпадают.")
+	}
+	return b.String()
+}
+
+// clauseText — клауза с ролью: одна и та же клауза как посылка и как
+// отрицание цели — разные формализации.
+func clauseText(c *resolution.Clause) string {
+	if c.IsGoal() {
+		return c.String() + " (отрицание цели)"
+	}
+	return c.String()
+}
+
+func clauseSet(clauses []*resolution.Clause) map[string]bool {
+	set := make(map[string]bool, len(clauses))
+	for _, c := range clauses {
+		set[clauseText(c)] = true
+	}
+	return set
+}
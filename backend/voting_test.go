@@ -0,0 +1,30 @@
+package backend
+
+import (
+	"neurosolver/resolution"
+	"testing"
+)
+
+func TestTally(t *testing.T) {
+	proved, notProved := resolution.StatusProved, resolution.StatusNotProved
+	cases := []struct {
+		name     string
+		verdicts []string
+		verdict  string
+		votes    int
+		tie      bool
+	}{
+		{"majority", []string{proved, notProved, proved}, proved, 2, false},
+		{"tie 1-1", []string{proved, notProved}, notProved, 1, true},
+		{"tie 2-2", []string{proved, proved, notProved, notProved}, notProved, 2, true},
+		{"tie with inconsistent", []string{proved, verdictInconsistent}, verdictInconsistent, 1, true},
+		{"errors do not vote", []string{proved, verdictError, verdictError}, proved, 1, false},
+		{"only errors", []string{verdictError, verdictError}, "", 0, false},
+	}
+	for _, c := range cases {
+		verdict, votes, tie := tally(c.verdicts)
+		if verdict != c.verdict || votes != c.votes || tie != c.tie {
+			t.Errorf("%s: got (%q, %d, %v), want (%q, %d, %v)", c.name, verdict, votes, tie, c.verdict, c.votes, c.tie)
+		}
+	}
+}
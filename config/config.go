@@ -0,0 +1,155 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config — настройки приложения, хранятся в JSON-файле в каталоге
+// пользовательских настроек (см. Path). Пустые поля провайдера означают
+// значения из переменных окружения или по умолчанию. Ключ API в файле
+// не хранится — для него есть пакет keystore.
+type Config struct {
+	Provider string `json:"provider"` // openai, anthropic или ollama
+	BaseURL  string `json:"base_url"`
+	Model    string `json:"model"`
+
+	ParsingTemperature     float64 `json:"parsing_temperature"`
+	ExplanationTemperature float64 `json:"explanation_temperature"`
+
+	MaxIterations int `json:"max_iterations"` // лимит проверок пар клауз
+	Port          int `json:"port"`           // порт встроенного сервера, применяется после перезапуска
+}
+
+// Default возвращает настройки по умолчанию (прежние значения в коде).
+func Default() Config {
+	return Config{
+		ParsingTemperature:     0.2,
+		ExplanationTemperature: 1,
+		MaxIterations:          500000,
+		Port:                   51115,
+	}
+}
+
+// Normalize убирает пробелы вокруг адреса API и имени модели. Проверять,
+// сохранять и применять нужно одни и те же нормализованные настройки.
+func (c Config) Normalize() Config {
+	c.BaseURL = strings.TrimSpace(c.BaseURL)
+	c.Model = strings.TrimSpace(c.Model)
+	return c
+}
+
+// Validate проверяет настройки и возвращает первую найденную ошибку.
+func (c Config) Validate() error {
+	switch c.Provider {
+	case "", "openai", "anthropic", "ollama":
+	default:
+		return fmt.Errorf("неизвестный провайдер: %q", c.Provider)
+	}
+	// У anthropic и ollama нет модели по умолчанию
+	if (c.Provider == "anthropic" || c.Provider == "ollama") && strings.TrimSpace(c.Model) == "" {
+		return fmt.Errorf("для провайдера %s нужно указать модель", c.Provider)
+	}
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("некорректный адрес API: %q", c.BaseURL)
+		}
+	}
+	if c.ParsingTemperature < 0 || c.ParsingTemperature > 2 {
+		return errors.New("температура разбора должна быть от 0 до 2")
+	}
+	if c.ExplanationTemperature < 0 || c.ExplanationTemperature > 2 {
+		return errors.New("температура объяснения должна быть от 0 до 2")
+	}
+	if c.MaxIterations < 100 || c.MaxIterations > 50000000 {
+		return errors.New("лимит итераций должен быть от 100 до 50 000 000")
+	}
+	if c.Port < 1024 || c.Port > 65535 {
+		return errors.New("порт должен быть от 1024 до 65535")
+	}
+	return nil
+}
+
+// Path возвращает путь к файлу настроек: <UserConfigDir>/neurosolver/config.json.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "neurosolver", "config.json"), nil
+}
+
+// Load читает настройки. Если файла нет, возвращаются настройки по умолчанию;
+// отсутствующие в файле поля тоже берутся по умолчанию.
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile читает настройки из указанного файла.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("ошибка чтения настроек %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Default(), fmt.Errorf("ошибка в настройках %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save проверяет и записывает настройки.
+func Save(cfg Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	return SaveFile(path, cfg)
+}
+
+// SaveFile проверяет настройки и атомарно записывает их в файл
+// с правами только для владельца. Ключ API в файл не попадает — он хранится
+// в keystore.
+func SaveFile(path string, cfg Config) error {
+	cfg = cfg.Normalize()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMissingFileReturnsDefaults(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != Default() {
+		t.Fatalf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "neurosolver", "config.json")
+	cfg := Default()
+	cfg.Provider = "ollama"
+	cfg.Model = " llama3.1 "
+	cfg.MaxIterations = 1000
+
+	if err := SaveFile(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Model != "llama3.1" || loaded.MaxIterations != 1000 || loaded.Port != 51115 {
+		t.Fatalf("unexpected config: %+v", loaded)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cfg := Default()
+	cfg.BaseURL, cfg.Model = " https://api.example.com/v1 ", "model "
+	cfg = cfg.Normalize()
+	if cfg.BaseURL != "https://api.example.com/v1" || cfg.Model != "model" {
+		t.Fatalf("unexpected normalized config: %+v", cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for name, mutate := range map[string]func(*Config){
+		"provider":    func(c *Config) { c.Provider = "gpt" },
+		"model":       func(c *Config) { c.Provider, c.Model = "anthropic", " " },
+		"url":         func(c *Config) { c.BaseURL = "localhost:11434" },
+		"temperature": func(c *Config) { c.ParsingTemperature = 3 },
+		"iterations":  func(c *Config) { c.MaxIterations = 0 },
+		"port":        func(c *Config) { c.Port = 80 },
+	} {
+		cfg := Default()
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
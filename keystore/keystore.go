@@ -0,0 +1,205 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Хранилище ключа API: связка ключей ОС (Secret Service на Linux,
+// Credential Manager на Windows), а без неё — файл, зашифрованный
+// AES-GCM ключом из пароля пользователя (PBKDF2-SHA256).
+
+const (
+	service = "neurosolver"
+	user    = "api_key"
+
+	pbkdf2Iterations = 600000
+	saltSize         = 16
+	keySize          = 32
+)
+
+var (
+	ErrLocked       = errors.New("ключ API зашифрован: введите пароль в настройках")
+	ErrNoPassphrase = errors.New("связка ключей недоступна: для шифрования ключа нужен пароль")
+	ErrBadPassword  = errors.New("неверный пароль")
+)
+
+var (
+	mu       sync.Mutex
+	unlocked string // ключ из файла, расшифрованный в этом сеансе
+)
+
+// encryptedFile — формат файла с зашифрованным ключом
+type encryptedFile struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func filePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "neurosolver", "apikey.enc"), nil
+}
+
+// KeyringAvailable сообщает, работает ли связка ключей ОС.
+func KeyringAvailable() bool {
+	_, err := keyring.Get(service, user)
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// Get возвращает сохранённый ключ или пустую строку: сначала из связки
+// ключей, затем расшифрованный в этом сеансе ключ из файла.
+func Get() string {
+	if key, err := keyring.Get(service, user); err == nil {
+		return key
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return unlocked
+}
+
+// Locked сообщает, что ключ хранится в зашифрованном файле и ещё не
+// расшифрован паролем.
+func Locked() bool {
+	if Get() != "" {
+		return false
+	}
+	path, err := filePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Save сохраняет ключ в связку ключей, а если она недоступна — в файл,
+// зашифрованный паролем passphrase.
+func Save(key, passphrase string) error {
+	if err := keyring.Set(service, user, key); err == nil {
+		return nil
+	}
+	if passphrase == "" {
+		return ErrNoPassphrase
+	}
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+	if err := saveFile(path, key, passphrase); err != nil {
+		return err
+	}
+	mu.Lock()
+	unlocked = key
+	mu.Unlock()
+	return nil
+}
+
+// Unlock расшифровывает ключ из файла на время сеанса.
+func Unlock(passphrase string) error {
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+	key, err := loadFile(path, passphrase)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	unlocked = key
+	mu.Unlock()
+	return nil
+}
+
+// Delete удаляет ключ из связки ключей и файла.
+func Delete() error {
+	mu.Lock()
+	unlocked = ""
+	mu.Unlock()
+	if err := keyring.Delete(service, user); err != nil && !errors.Is(err, keyring.ErrNotFound) && KeyringAvailable() {
+		return err
+	}
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func saveFile(path, key, passphrase string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.Marshal(encryptedFile{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, []byte(key), nil),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func loadFile(path, passphrase string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var f encryptedFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("повреждён файл ключа %s: %w", path, err)
+	}
+	aead, err := deriveKey(passphrase, f.Salt)
+	if err != nil {
+		return "", err
+	}
+	if len(f.Nonce) != aead.NonceSize() {
+		return "", fmt.Errorf("повреждён файл ключа %s", path)
+	}
+	key, err := aead.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return "", ErrBadPassword
+	}
+	return string(key), nil
+}
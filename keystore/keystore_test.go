@@ -0,0 +1,53 @@
+package keystore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apikey.enc")
+	if err := saveFile(path, "sk-secret", "пароль"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := loadFile(path, "пароль")
+	if err != nil || key != "sk-secret" {
+		t.Fatalf("got %q, %v", key, err)
+	}
+	if _, err := loadFile(path, "другой"); !errors.Is(err, ErrBadPassword) {
+		t.Fatalf("expected ErrBadPassword, got %v", err)
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+	if !KeyringAvailable() {
+		t.Fatal("mock keyring should be available")
+	}
+	if err := Save("sk-secret", ""); err != nil {
+		t.Fatal(err)
+	}
+	if Get() != "sk-secret" || Locked() {
+		t.Fatalf("unexpected state: %q, locked=%v", Get(), Locked())
+	}
+	if err := Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if Get() != "" {
+		t.Fatal("key was not deleted")
+	}
+}
+
+func TestSaveWithoutKeyringNeedsPassphrase(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no secret service"))
+	if KeyringAvailable() {
+		t.Fatal("keyring should be unavailable")
+	}
+	if err := Save("sk-secret", ""); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf("expected ErrNoPassphrase, got %v", err)
+	}
+}
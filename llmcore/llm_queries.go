@@ -5,74 +5,84 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"neurosolver/keystore"
 	"os"
-	"strings"
-
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/option"
 )
 
 const base_url string = "https://api.mistral.ai/v1"
 const model string = "mistral-medium-latest"
 
-var embeddedAPIKey string = ""
-
 // Ошибки LLM
 var (
 	ErrRateLimitExceeded = errors.New("превышен лимит запросов API")
-	ErrAPIKeyMissing     = errors.New("API ключ не найден (установите переменную OPENAI_API_KEY)")
+	ErrAPIKeyMissing     = errors.New("API ключ не найден (введите его в настройках или установите переменную OPENAI_API_KEY)")
 	ErrEmptyResponse     = errors.New("получен пустой ответ от LLM")
 )
 
+// getAPIKey возвращает ключ из переменной OPENAI_API_KEY, а если она
+// не задана — из хранилища ключей (см. пакет keystore).
 func getAPIKey() string {
 	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
 		return key
 	}
-	return embeddedAPIKey
+	return keystore.Get()
 }
 
-// LLMQuery выполняет запрос к LLM и возвращает результат или ошибку
-func LLMQuery(systemPrompt, userPrompt string, temperature float64) (string, error) {
-	apiKey := getAPIKey()
-	if apiKey == "" {
-		return "", ErrAPIKeyMissing
-	}
-
-	client := openai.NewClient(option.WithBaseURL(base_url), option.WithAPIKey(apiKey))
-
-	resp, err := client.Chat.Completions.New(context.TODO(),
-		openai.ChatCompletionNewParams{
-			Model:       model,
-			Temperature: openai.Float(temperature),
-			Messages: []openai.ChatCompletionMessageParamUnion{
-				openai.SystemMessage(systemPrompt),
-				openai.UserMessage(userPrompt),
-			},
-		})
+// LLMQuery выполняет запрос к текущему провайдеру LLM и возвращает результат
+// или ошибку. Попытки ограничены по времени и повторяются при 429 и 5xx
+// (см. DefaultRetryPolicy); отмена ctx прерывает и запрос, и ожидание повтора.
+func LLMQuery(ctx context.Context, systemPrompt, userPrompt string, temperature float64) (string, error) {
+	return LLMChat(ctx, Request{
+		System:      systemPrompt,
+		Messages:    []Message{{Role: RoleUser, Content: userPrompt}},
+		Temperature: temperature,
+	})
+}
 
+// LLMChat — как LLMQuery, но с произвольным запросом: историей диалога
+// (последняя реплика — пользователя) и схемой ответа.
+func LLMChat(ctx context.Context, req Request) (string, error) {
+	p, err := currentProvider()
 	if err != nil {
-		// Проверяем на rate limit (429)
-		errStr := err.Error()
-		if strings.Contains(errStr, "429") || strings.Contains(errStr, "Rate limit") {
-			return "", ErrRateLimitExceeded
-		}
-		return "", fmt.Errorf("ошибка API: %w", err)
-	}
-
-	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
-		return "", ErrEmptyResponse
+		return "", err
 	}
-
-	return resp.Choices[0].Message.Content, nil
+	return DefaultRetryPolicy.Complete(ctx, p, req)
 }
 
+// ParseStringList разбирает JSON-массив строк; ограждения Markdown и текст
+// вокруг массива отбрасываются (см. ExtractJSON).
 func ParseStringList(input string) ([]string, error) {
 	var result []string
 
-	err := json.Unmarshal([]byte(input), &result)
+	err := json.Unmarshal([]byte(ExtractJSON(input)), &result)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
 	}
 
 	return result, nil
 }
+
+// ParsedClause — клауза, возвращённая LLM, вместе с фрагментом исходного
+// текста, из которого она получена, и ролью: "axiom", "hypothesis"
+// или "negated_conjecture" (отрицание цели).
+type ParsedClause struct {
+	Clause string `json:"clause"`
+	Source string `json:"source"`
+	Role   string `json:"role,omitempty"`
+}
+
+// ParseClauseList разбирает ответ LLM: массив объектов {"clause", "source",
+// "role"}. Простой массив строк не принимается — в нём нет ролей, и цель
+// нельзя отличить от посылок.
+func ParseClauseList(input string) ([]ParsedClause, error) {
+	var result []ParsedClause
+	if err := json.Unmarshal([]byte(ExtractJSON(input)), &result); err != nil {
+		return nil, fmt.Errorf("ожидался массив объектов {\"clause\", \"source\", \"role\"}: %w", err)
+	}
+	for i, c := range result {
+		if c.Clause == "" {
+			return nil, fmt.Errorf("элемент %d не содержит поля \"clause\"", i)
+		}
+	}
+	return result, nil
+}
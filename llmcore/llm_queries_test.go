@@ -1,6 +1,7 @@
 package llmcore
 
 import (
+	"context"
 	"os"
 	"testing"
 )
@@ -72,6 +73,43 @@ func TestParseStringList_Unicode(t *testing.T) {
 	}
 }
 
+func TestParseClauseList_Objects(t *testing.T) {
+	input := `[
+		{"clause": "¬Человек(x) ∨ Смертен(x)", "source": "Все люди смертны."},
+		{"clause": "Человек(Сократ)", "source": "Сократ — человек.", "role": "hypothesis"},
+		{"clause": "¬Смертен(Сократ)", "source": "Докажи, что Сократ смертен.", "role": "negated_conjecture"}
+	]`
+
+	result, err := ParseClauseList(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 3 {
+		t.Fatalf("length mismatch: got %d, want 3", len(result))
+	}
+	if result[1].Clause != "Человек(Сократ)" || result[1].Source != "Сократ — человек." || result[1].Role != "hypothesis" {
+		t.Errorf("element 1: got %+v", result[1])
+	}
+	if result[2].Role != "negated_conjecture" {
+		t.Errorf("element 2 must be the negated goal: got %+v", result[2])
+	}
+}
+
+func TestParseClauseList_PlainStrings(t *testing.T) {
+	// В массиве строк нет ролей — цель не отличить от посылок
+	if _, err := ParseClauseList(`["Человек(Сократ)", "¬Смертен(Сократ)"]`); err == nil {
+		t.Fatal("expected error for plain string array, got nil")
+	}
+}
+
+func TestParseClauseList_MissingClause(t *testing.T) {
+	_, err := ParseClauseList(`[{"source": "Сократ — человек."}]`)
+	if err == nil {
+		t.Fatal("expected error for object without clause, got nil")
+	}
+}
+
 // TestLLMQuery_Connection проверяет, что API доступен и возвращает ответ.
 // Этот тест пропускается, если не установлена переменная окружения OPENROUTER_API_KEY
 // или если передан флаг -short.
@@ -90,7 +128,7 @@ func TestLLMQuery_Connection(t *testing.T) {
 	systemPrompt := "You are a helpful assistant. Respond with exactly one word."
 	userPrompt := "Say 'pong'"
 
-	result, err := LLMQuery(systemPrompt, userPrompt, 0.1)
+	result, err := LLMQuery(context.Background(), systemPrompt, userPrompt, 0.1)
 	if err != nil {
 		t.Fatalf("LLM query failed: %v", err)
 	}
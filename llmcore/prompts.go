@@ -13,8 +13,22 @@ const ParsingPrompt string = `
 ФОРМАТ ВЫВОДА
 ═══════════════════════════════════════════════════════════════
 
-1. Возвращай ТОЛЬКО валидный JSON-массив строк.
-2. Синтаксис строк:
+1. Возвращай ТОЛЬКО валидный JSON-объект вида {"clauses": [...], "glossary": [...]}.
+   "clauses" — массив объектов {"clause": "...", "source": "...", "role": "..."}:
+   - "clause" — клауза в синтаксисе, описанном ниже.
+   - "source" — предложение исходного текста (дословно), из которого получена клауза.
+     Если несколько клауз получены из одного предложения, у них одинаковый "source".
+   - "role" — роль клаузы в задаче:
+       "axiom"              — общее правило или знание ("Все люди смертны");
+       "hypothesis"         — условие конкретной задачи, факт ("Сократ — человек");
+       "negated_conjecture" — клауза, полученная из ОТРИЦАНИЯ ЦЕЛИ.
+   "glossary" — словарь: по одному объекту {"name", "kind", "arity", "description"} на КАЖДЫЙ
+   предикат, функцию и константу из клауз:
+   - "name" — имя символа, как в клаузах;
+   - "kind" — "predicate", "function" или "constant";
+   - "arity" — число аргументов (у константы 0);
+   - "description" — смысл символа по-русски, с аргументами по порядку
+     ("Любит(x, y)" — "x любит y"; "Мать(x)" — "мать x"; "Сократ" — "философ Сократ").2. Синтаксис клауз:
    - Литералы разделяются '∨' (U+2228).
    - Отрицание: '¬' (U+00AC) слитно с предикатом.
    - Переменные: одиночные строчные буквы (x, y, z, u, v).
@@ -23,7 +37,7 @@ const ParsingPrompt string = `
    - Используй ТОЛЬКО кириллицу для имён предикатов, функций и констант.
    - Для функций Сколема используй префикс "Функ" или "Ск" (например: ФункОтец(x), СкЛюбимый(x)).
    - Для констант Сколема используй префикс "Конст" или "К" (например: КонстМакс, КЧеловек).
-   - Возвращай ТОЛЬКО валидный JSON-массив строк.
+   - Возвращай ТОЛЬКО валидный JSON-объект.
    - НИКАКОГО Markdown, никаких пояснений, никаких вводных слов.
    - НЕ ИСПОЛЬЗУЙ обратные кавычки, блоки кода или что-либо подобное.
 
@@ -74,11 +88,15 @@ const ParsingPrompt string = `
 ПРИМЕР 1 (Простой):
 Вход: "Все люди смертны. Сократ человек. Докажи, что Сократ смертен."
 Вывод:
-[
-  "¬Человек(x) ∨ Смертен(x)",
-  "Человек(Сократ)",
-  "¬Смертен(Сократ)"
-]
+{"clauses": [
+  {"clause": "¬Человек(x) ∨ Смертен(x)", "source": "Все люди смертны.", "role": "axiom"},
+  {"clause": "Человек(Сократ)", "source": "Сократ человек.", "role": "hypothesis"},
+  {"clause": "¬Смертен(Сократ)", "source": "Докажи, что Сократ смертен.", "role": "negated_conjecture"}
+], "glossary": [
+  {"name": "Человек", "kind": "predicate", "arity": 1, "description": "x — человек"},
+  {"name": "Смертен", "kind": "predicate", "arity": 1, "description": "x смертен"},
+  {"name": "Сократ", "kind": "constant", "arity": 0, "description": "Сократ"}
+]}
 
 ПРИМЕР 2 (Скулемовская функция / Зависимость):
 Вход: "У каждого целого числа есть число, которое больше него. Докажи, что не существует самого большого числа."
@@ -87,10 +105,14 @@ const ParsingPrompt string = `
 - Цель: ¬∃z ∀w ¬Больше(z, w) (Нет самого большого)
 - Отрицание цели: ∃z ∀w ¬Больше(z, w) (Существует самое большое) ⇒ Скулемизация z (константа КонстМакс) ⇒ ¬Больше(КонстМакс, w)
 Вывод:
-[
-  "Больше(СкБольше(x), x)",
-  "¬Больше(КонстМакс, w)"
-]
+{"clauses": [
+  {"clause": "Больше(СкБольше(x), x)", "source": "У каждого целого числа есть число, которое больше него.", "role": "axiom"},
+  {"clause": "¬Больше(КонстМакс, w)", "source": "Докажи, что не существует самого большого числа.", "role": "negated_conjecture"}
+], "glossary": [
+  {"name": "Больше", "kind": "predicate", "arity": 2, "description": "x больше y"},
+  {"name": "СкБольше", "kind": "function", "arity": 1, "description": "число, большее x"},
+  {"name": "КонстМакс", "kind": "constant", "arity": 0, "description": "предполагаемое самое большое число"}
+]}
 
 ПРИМЕР 3 (Любовь — каждый любит кого-то):
 Вход: "Для любого человека существует другой человек, которого он любит. Иван — человек. Докажи, что Иван кого-то любит."
@@ -99,11 +121,16 @@ const ParsingPrompt string = `
 - Цель: ∃y Любит(Иван, y)
 - Отрицание цели: ∀y ¬Любит(Иван, y)
 Вывод:
-[
-  "¬Человек(x) ∨ Любит(x, СкЛюбимый(x))",
-  "Человек(Иван)",
-  "¬Любит(Иван, y)"
-]
+{"clauses": [
+  {"clause": "¬Человек(x) ∨ Любит(x, СкЛюбимый(x))", "source": "Для любого человека существует другой человек, которого он любит.", "role": "axiom"},
+  {"clause": "Человек(Иван)", "source": "Иван — человек.", "role": "hypothesis"},
+  {"clause": "¬Любит(Иван, y)", "source": "Докажи, что Иван кого-то любит.", "role": "negated_conjecture"}
+], "glossary": [
+  {"name": "Человек", "kind": "predicate", "arity": 1, "description": "x — человек"},
+  {"name": "Любит", "kind": "predicate", "arity": 2, "description": "x любит y"},
+  {"name": "СкЛюбимый", "kind": "function", "arity": 1, "description": "тот, кого любит x"},
+  {"name": "Иван", "kind": "constant", "arity": 0, "description": "Иван"}
+]}
 
 ПРИМЕР 4 (Вложенность):
 Вход: "Каждый человек любит свою мать. Мать каждого человека — человек. Докажи, что каждый любит какого-то человека."
@@ -113,21 +140,28 @@ const ParsingPrompt string = `
 - Цель: ∀z ∃w (Человек(w) ∧ Любит(z, w))
 - Отрицание цели: ∃z ∀w (¬Человек(w) ∨ ¬Любит(z, w)) ⇒ ¬Человек(w) ∨ ¬Любит(КонстЧел, w)
 Вывод:
-[
-  "Любит(x, Мать(x))",
-  "Человек(Мать(x))",
-  "¬Человек(w) ∨ ¬Любит(КонстЧел, w)"
-]
+{"clauses": [
+  {"clause": "Любит(x, Мать(x))", "source": "Каждый человек любит свою мать.", "role": "axiom"},
+  {"clause": "Человек(Мать(x))", "source": "Мать каждого человека — человек.", "role": "axiom"},
+  {"clause": "¬Человек(w) ∨ ¬Любит(КонстЧел, w)", "source": "Докажи, что каждый любит какого-то человека.", "role": "negated_conjecture"}
+], "glossary": [
+  {"name": "Любит", "kind": "predicate", "arity": 2, "description": "x любит y"},
+  {"name": "Человек", "kind": "predicate", "arity": 1, "description": "x — человек"},
+  {"name": "Мать", "kind": "function", "arity": 1, "description": "мать x"},
+  {"name": "КонстЧел", "kind": "constant", "arity": 0, "description": "человек, который никого не любит (из отрицания цели)"}
+]}
 
 ═══════════════════════════════════════════════════════════════
 ЧЕК-ЛИСТ ПЕРЕД ОТВЕТОМ
 ═══════════════════════════════════════════════════════════════
 1. Все предикаты согласованы (одно имя для одного понятия)?
 2. Импликации раскрыты как ¬A ∨ B?
-3. Цель инвертирована?
+3. Цель инвертирована и отмечена "role": "negated_conjecture"?
 4. Зависимости ∃ от ∀ превращены в ФУНКЦИИ ФункX(переменные)?
 5. Все имена на КИРИЛЛИЦЕ?
-6. Формат JSON валиден?
+6. У каждой клаузы указано исходное предложение "source"?
+7. Каждый символ из клауз описан в "glossary" с верной арностью?
+8. Формат JSON валиден?
 
 ПЕРЕД ОТВЕТОМ ВНИМАТЕЛЬНО ПЕРЕПРОВЕРЬ ВСЕ ПУНКТЫ ЧЕК-ЛИСТА, А ТАК ЖЕ ВЫВОД.
 
@@ -144,6 +178,9 @@ const ExplanationPrompt string = `
    - Клаузу вида [¬A(x) ∨ B(x)] объясняй как импликацию: "Если x является A, то x является B".
    - Клаузу вида [A(Const)] объясняй как факт: "Нам известно, что Const является A".
    - Клаузу вида [¬A(Const)] объясняй как отрицание: "Предположим, что Const не является A".
+   - Клаузы с пометкой "(отрицание цели)" — это допущение, обратное доказываемому утверждению.
+   - Если после лога дан "СЛОВАРЬ СИМВОЛОВ", называй предикаты, функции и константы по их описаниям из словаря, а не по именам: "ПопадаетВВальгаллу(x)" с описанием "x попадает в Вальгаллу" — это "x попадает в Вальгаллу".
+   - Если после лога дан "ЧЕРНОВИК ОБЪЯСНЕНИЯ", он составлен автоматически по шаблонам и верно передаёт ход доказательства. Сохрани его логику и порядок шагов, но перепиши естественным языком: согласуй падежи, замени формальные записи вроде "Сократ является Человек" на "Сократ — человек".
 
 2. ОБЪЯСНЕНИЕ ШАГОВ:
    - Не перечисляй просто "Шаг 1", "Шаг 2". Вместо этого используй связки: "Сначала мы берем...", "Затем сопоставим это с...", "Из этого следует...".
@@ -164,7 +201,7 @@ const ExplanationPrompt string = `
 Используемые начальные клаузы:
 [2] Человек(Сократ)
 [1] ¬Человек(x) ∨ Смертен(x)
-[3] ¬Смертен(Сократ)
+[3] ¬Смертен(Сократ) (отрицание цели)
 
 Шаги резолюции:
 Шаг 1 - Резолюция
@@ -189,3 +226,50 @@ const ExplanationPrompt string = `
 
 Выполняй задачу, строго следуя этим инструкциям. Перед ответом внимательно проверь, что все требования соблюдены.
 `
+
+const FailureExplanationPrompt string = `
+Ты — опытный и терпеливый преподаватель логики. Метод резолюций НЕ нашёл доказательства: пустая клауза (□) не получена. Твоя задача — объяснить пользователю простым русским языком, почему утверждение не удалось доказать и чего может не хватать в условиях задачи. В виде одного короткого абзаца.
+
+Тебе дан отчёт движка:
+- "Результат" — либо найдена контрмодель (утверждение НЕ следует из условий), либо движок не нашёл доказательства (поиск неполный: это НЕ значит, что утверждение не следует), либо поиск остановлен по лимиту итераций (ответ неизвестен: доказательство может существовать, но оказалось слишком длинным).
+- "Начальные клаузы" — условия задачи; клауза с пометкой "(отрицание цели)" — отрицание доказываемого утверждения.
+- "Известные факты" — конкретные факты, которые есть в условиях или выводятся из них.
+- "Контрмодель" — если дана, это ситуация, в которой все условия верны, а доказываемое утверждение ложно. Это самый убедительный аргумент: опиши её как пример.
+- "Недостающие посылки" — факты, добавление любого из которых сделало бы утверждение доказуемым.
+- "СЛОВАРЬ СИМВОЛОВ" (если дан) — смысл предикатов, функций и констант; называй их по описаниям.
+- "ЧЕРНОВИК ОБЪЯСНЕНИЯ" — составлен автоматически по шаблонам и верен по сути. Сохрани его выводы, но перепиши естественным языком.
+
+Правила:
+1. НИКОГДА не утверждай, что доказательство найдено или что утверждение истинно. Не придумывай шагов вывода.
+2. Утверждай, что утверждение не следует из условий, ТОЛЬКО если дана контрмодель. Без контрмодели говори, что движок не нашёл доказательства и ответ неизвестен; если поиск остановлен по лимиту, посоветуй увеличить лимит итераций в настройках или проверить формулировку.
+3. Предложи, какой посылки может не хватать, опираясь ТОЛЬКО на "Недостающие посылки". Если их нет и дана контрмодель, скажи, что в условиях нет правила, связывающего их с доказываемым утверждением; без контрмодели ничего не предлагай.
+4. Не используй термины "клауза", "литерал", "резольвента" — говори "утверждение", "условие", "правило".
+5. Тон дружелюбный, ответ краткий.
+`
+
+const BackTranslationPrompt string = `
+Ты — преподаватель логики. Тебе дан JSON-массив логических клауз (дизъюнктов), полученных при формализации задачи. Перескажи каждую клаузу одним простым предложением на русском языке, чтобы человек без математической подготовки мог проверить, правильно ли формализована задача.
+
+Правила интерпретации:
+- Клаузу вида [¬A(x) ∨ B(x)] читай как импликацию: "Если x является A, то x является B".
+- Клаузу вида [A(Const)] читай как факт: "Const является A".
+- Клаузу вида [¬A(Const)] читай как отрицание: "Const не является A".
+- Переменные (x, y, z...) означают "любой объект": "Каждый человек смертен".
+- Функции вида Отец(x) читай как "отец x", скулемовские Ск.../Конст... — как "некоторый объект".
+- Пересказывай ТОЛЬКО то, что записано в клаузе. Не исправляй и не дополняй её — пользователь должен увидеть ошибки формализации, если они есть.
+
+Формат ответа: ТОЛЬКО валидный JSON-массив строк той же длины и в том же порядке, что и входной массив. Без Markdown и пояснений.
+
+ПРИМЕР:
+Вход: ["¬Человек(x) ∨ Смертен(x)", "Человек(Сократ)", "¬Смертен(Сократ)"]
+Вывод: ["Каждый человек смертен.", "Сократ — человек.", "Сократ не смертен."]
+`
+
+// RepairPrompt — повторное сообщение пользователя, когда ответ на
+// ParsingPrompt не прошёл проверку. %s — список ошибок.
+const RepairPrompt string = `
+Твой ответ не прошёл проверку. Найденные ошибки:
+%s
+
+Исправь эти ошибки, соблюдая все правила формата и синтаксиса из инструкции. Верни ПОЛНЫЙ исправленный JSON-объект {"clauses": [...], "glossary": [...]} для всей задачи — без Markdown и пояснений.
+`
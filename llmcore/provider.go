@@ -0,0 +1,374 @@
+package llmcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// Роли сообщений диалога
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message — реплика диалога с моделью (системный промпт передаётся отдельно).
+type Message struct {
+	Role    string
+	Content string
+}
+
+// Request — запрос к модели.
+type Request struct {
+	System      string
+	Messages    []Message
+	Temperature float64
+	Schema      *JSONSchema // схема ответа; nil — свободный текст
+}
+
+// Provider — бэкенд LLM. Реализации: OpenAI-совместимые API (OpenAI,
+// Mistral, OpenRouter, llama.cpp server), Anthropic Messages API и Ollama.
+type Provider interface {
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+// Типы провайдеров
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+)
+
+// ProviderConfig — настройки провайдера. Пустые BaseURL и Model заменяются
+// значениями по умолчанию, если они есть у провайдера.
+type ProviderConfig struct {
+	Kind    string
+	BaseURL string
+	Model   string
+	APIKey  string
+}
+
+const (
+	defaultAnthropicURL = "https://api.anthropic.com"
+	defaultOllamaURL    = "http://localhost:11434"
+	anthropicVersion    = "2023-06-01"
+	anthropicMaxTokens  = 4096
+
+	// noAPIKey отправляется локальным OpenAI-совместимым серверам
+	// (llama.cpp server), которым ключ не нужен
+	noAPIKey = "sk-no-key-required"
+)
+
+// NewProvider создаёт провайдер по настройкам.
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	switch cfg.Kind {
+	case ProviderOpenAI, "":
+		// Ключ обязателен только для API по умолчанию: локальные серверы
+		// с собственным адресом обычно работают без него
+		switch {
+		case cfg.BaseURL == "" && cfg.APIKey == "":
+			return nil, ErrAPIKeyMissing
+		case cfg.APIKey == "":
+			cfg.APIKey = noAPIKey
+		case cfg.BaseURL == "":
+			cfg.BaseURL = base_url
+		}
+		if cfg.Model == "" {
+			cfg.Model = model
+		}
+		return &openAIProvider{
+			model: cfg.Model,
+			// Повторы делает RetryPolicy, чтобы не умножать их на повторы SDK
+			client: openai.NewClient(option.WithBaseURL(cfg.BaseURL), option.WithAPIKey(cfg.APIKey), option.WithMaxRetries(0)),
+		}, nil
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, ErrAPIKeyMissing
+		}
+		if cfg.Model == "" {
+			return nil, errors.New("не задана модель для провайдера anthropic")
+		}
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = defaultAnthropicURL
+		}
+		return &anthropicProvider{baseURL: strings.TrimRight(cfg.BaseURL, "/"), model: cfg.Model, apiKey: cfg.APIKey, http: http.DefaultClient}, nil
+	case ProviderOllama:
+		if cfg.Model == "" {
+			return nil, errors.New("не задана модель для провайдера ollama")
+		}
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = defaultOllamaURL
+		}
+		return &ollamaProvider{baseURL: strings.TrimRight(cfg.BaseURL, "/"), model: cfg.Model, http: http.DefaultClient}, nil
+	}
+	return nil, fmt.Errorf("неизвестный провайдер LLM: %q", cfg.Kind)
+}
+
+// ConfigFromEnv читает настройки провайдера из переменных окружения
+// NEUROSOLVER_LLM_PROVIDER, NEUROSOLVER_LLM_BASE_URL, NEUROSOLVER_LLM_MODEL;
+// ключ — OPENAI_API_KEY или сохранённый в хранилище ключей.
+func ConfigFromEnv() ProviderConfig {
+	return ProviderConfig{
+		Kind:    os.Getenv("NEUROSOLVER_LLM_PROVIDER"),
+		BaseURL: os.Getenv("NEUROSOLVER_LLM_BASE_URL"),
+		Model:   os.Getenv("NEUROSOLVER_LLM_MODEL"),
+		APIKey:  getAPIKey(),
+	}
+}
+
+var (
+	providerMu  sync.Mutex
+	provider    Provider
+	configured  bool  // Configure вызывался: окружение больше не используется
+	configError error // ошибка последнего Configure
+)
+
+// Configure заменяет текущий провайдер. Действует на все последующие запросы.
+// Если провайдер не создан, запросы возвращают эту ошибку до следующего
+// успешного Configure: прежний провайдер (например, с удалённым ключом) или
+// провайдер из окружения вместо выбранного пользователем не используются.
+func Configure(cfg ProviderConfig) error {
+	p, err := NewProvider(cfg)
+	providerMu.Lock()
+	provider, configured, configError = p, true, err
+	providerMu.Unlock()
+	return err
+}
+
+// currentProvider возвращает настроенный провайдер; если Configure не
+// вызывался, при первом обращении провайдер создаётся по переменным
+// окружения. Клиент переиспользуется между запросами.
+func currentProvider() (Provider, error) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if configured {
+		return provider, configError
+	}
+	if provider == nil {
+		p, err := NewProvider(ConfigFromEnv())
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	}
+	return provider, nil
+}
+
+// ==========================================
+// OpenAI-совместимые API
+// ==========================================
+
+type openAIProvider struct {
+	model  string
+	client openai.Client
+	// noSchema выставляется, если сервер отверг response_format с JSON-схемой
+	// (не все OpenAI-совместимые API её поддерживают). Провайдер создаётся
+	// заново при смене модели, поэтому флаг относится только к этой модели.
+	noSchema atomic.Bool
+}
+
+func (p *openAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	messages := []openai.ChatCompletionMessageParamUnion{openai.SystemMessage(req.System)}
+	for _, m := range req.Messages {
+		if m.Role == RoleAssistant {
+			messages = append(messages, openai.AssistantMessage(m.Content))
+		} else {
+			messages = append(messages, openai.UserMessage(m.Content))
+		}
+	}
+
+	params := openai.ChatCompletionNewParams{
+		Model:       p.model,
+		Temperature: openai.Float(req.Temperature),
+		Messages:    messages,
+	}
+	useSchema := req.Schema != nil && !p.noSchema.Load()
+	if useSchema {
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
+				JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   req.Schema.Name,
+					Schema: req.Schema.Schema,
+					Strict: openai.Bool(true),
+				},
+			},
+		}
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, params)
+
+	if err != nil {
+		var apiErr *openai.Error
+		if useSchema && errors.As(err, &apiErr) && schemaRejected(apiErr) {
+			// Повторяем без схемы: ответ разберёт ExtractJSON
+			fmt.Println("LLM: структурированный вывод не поддерживается:", apiErr.Message)
+			p.noSchema.Store(true)
+			return p.Complete(ctx, req)
+		}
+		if errors.As(err, &apiErr) {
+			var header http.Header
+			if apiErr.Response != nil {
+				header = apiErr.Response.Header
+			}
+			return "", newAPIError(apiErr.StatusCode, header, apiErr.Message)
+		}
+		return "", fmt.Errorf("ошибка API: %w", err)
+	}
+
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// schemaRejected сообщает, что сервер отверг именно структурированный вывод:
+// другие ошибки 400 (длина контекста, неверные параметры) не должны навсегда
+// отключать схему.
+func schemaRejected(apiErr *openai.Error) bool {
+	if apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	text := strings.ToLower(apiErr.Param + " " + apiErr.Message)
+	return strings.Contains(text, "response_format") || strings.Contains(text, "json_schema")
+}
+
+// ==========================================
+// Anthropic Messages API
+// ==========================================
+
+type anthropicProvider struct {
+	baseURL string
+	model   string
+	apiKey  string
+	http    *http.Client
+}
+
+// chatMessage — сообщение в формате Anthropic и Ollama
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (p *anthropicProvider) Complete(ctx context.Context, req Request) (string, error) {
+	messages := make([]chatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = chatMessage{Role: m.Role, Content: m.Content}
+	}
+	body := map[string]interface{}{
+		"model":       p.model,
+		"max_tokens":  anthropicMaxTokens,
+		"system":      req.System,
+		"temperature": req.Temperature,
+		"messages":    messages,
+	}
+	headers := map[string]string{
+		"x-api-key":         p.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, p.http, p.baseURL+"/v1/messages", headers, body, &resp); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", ErrEmptyResponse
+	}
+	return text.String(), nil
+}
+
+// ==========================================
+// Ollama (/api/chat)
+// ==========================================
+
+type ollamaProvider struct {
+	baseURL string
+	model   string
+	http    *http.Client
+}
+
+func (p *ollamaProvider) Complete(ctx context.Context, req Request) (string, error) {
+	messages := []chatMessage{{Role: "system", Content: req.System}}
+	for _, m := range req.Messages {
+		messages = append(messages, chatMessage{Role: m.Role, Content: m.Content})
+	}
+	body := map[string]interface{}{
+		"model":    p.model,
+		"messages": messages,
+		"stream":   false,
+		"options":  map[string]interface{}{"temperature": req.Temperature},
+	}
+	if req.Schema != nil {
+		body["format"] = req.Schema.Schema
+	}
+
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := postJSON(ctx, p.http, p.baseURL+"/api/chat", nil, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+	return resp.Message.Content, nil
+}
+
+// postJSON отправляет JSON-запрос и разбирает JSON-ответ в out.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("ошибка API: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return newAPIError(resp.StatusCode, resp.Header, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("ошибка API: некорректный ответ: %w", err)
+	}
+	return nil
+}
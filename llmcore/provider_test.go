@@ -0,0 +1,132 @@
+package llmcore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnthropicProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("unexpected request: %s %v", r.URL.Path, r.Header)
+		}
+		var body struct {
+			System   string        `json:"system"`
+			Messages []chatMessage `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.System != "system" || len(body.Messages) != 1 || body.Messages[0].Content != "ping" {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		w.Write([]byte(`{"content": [{"type": "text", "text": "pong"}]}`))
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(ProviderConfig{Kind: ProviderAnthropic, BaseURL: server.URL, Model: "m", APIKey: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Complete(context.Background(), Request{System: "system", Messages: []Message{{Role: RoleUser, Content: "ping"}}})
+	if err != nil || got != "pong" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestOllamaProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []chatMessage `json:"messages"`
+			Stream   bool          `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/chat" || body.Stream || len(body.Messages) != 2 || body.Messages[0].Role != "system" {
+			t.Errorf("unexpected request: %s %+v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "pong"}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.Complete(context.Background(), Request{System: "system", Messages: []Message{{Role: RoleUser, Content: "ping"}}})
+	if err != nil || got != "pong" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestProviderRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	if _, err := p.Complete(context.Background(), Request{}); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("expected ErrRateLimitExceeded, got %v", err)
+	}
+}
+
+func TestConfigureErrorIsKept(t *testing.T) {
+	t.Cleanup(func() {
+		providerMu.Lock()
+		provider, configured, configError = nil, false, nil
+		providerMu.Unlock()
+	})
+	t.Setenv("OPENAI_API_KEY", "env-key")
+
+	// Выбранный провайдер не создан — окружение не подменяет его
+	if err := Configure(ProviderConfig{Kind: ProviderAnthropic, APIKey: "key"}); err == nil {
+		t.Fatal("expected error for anthropic without model")
+	}
+	if p, err := currentProvider(); err == nil || p != nil {
+		t.Fatalf("expected configuration error, got %v, %v", p, err)
+	}
+
+	if err := Configure(ProviderConfig{Kind: ProviderOllama, Model: "llama3"}); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := currentProvider(); err != nil || p == nil {
+		t.Fatalf("expected provider after successful Configure, got %v, %v", p, err)
+	}
+}
+
+func TestOpenAIProviderWithoutKey(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+
+	// Локальному серверу (llama.cpp) ключ не нужен
+	p, err := NewProvider(ProviderConfig{Kind: ProviderOpenAI, BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Complete(context.Background(), Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer "+noAPIKey {
+		t.Errorf("unexpected Authorization header %q", auth)
+	}
+}
+
+func TestNewProviderErrors(t *testing.T) {
+	for _, cfg := range []ProviderConfig{
+		{Kind: ProviderOpenAI},
+		{Kind: ProviderAnthropic, APIKey: "key"},
+		{Kind: ProviderOllama},
+		{Kind: "unknown"},
+	} {
+		if _, err := NewProvider(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
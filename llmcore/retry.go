@@ -0,0 +1,140 @@
+package llmcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ==========================================
+// Ошибки API и повторы запросов
+// ==========================================
+
+// APIError — ответ API с HTTP-статусом ошибки. Для статуса 429
+// errors.Is(err, ErrRateLimitExceeded) истинно.
+type APIError struct {
+	StatusCode int
+	RetryAfter time.Duration // из заголовка Retry-After, 0 — не задан
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimitExceeded.Error()
+	}
+	msg := fmt.Sprintf("ошибка API: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimitExceeded && e.StatusCode == http.StatusTooManyRequests
+}
+
+// Temporary сообщает, что запрос имеет смысл повторить (429 и 5xx).
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newAPIError собирает APIError по статусу и заголовкам ответа.
+func newAPIError(status int, header http.Header, message string) *APIError {
+	return &APIError{StatusCode: status, RetryAfter: parseRetryAfter(header, time.Now()), Message: message}
+}
+
+// parseRetryAfter разбирает Retry-After: число секунд или HTTP-дату.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// RetryPolicy — таймаут одной попытки и экспоненциальная задержка между
+// попытками со случайным разбросом.
+type RetryPolicy struct {
+	Timeout       time.Duration // таймаут одной попытки
+	MaxRetries    int           // число повторов после первой попытки
+	BaseDelay     time.Duration // задержка перед первым повтором
+	MaxDelay      time.Duration // предел экспоненциальной задержки
+	MaxRetryAfter time.Duration // предел ожидания по Retry-After
+}
+
+// DefaultRetryPolicy используется LLMQuery.
+var DefaultRetryPolicy = RetryPolicy{
+	Timeout:       90 * time.Second,
+	MaxRetries:    4,
+	BaseDelay:     time.Second,
+	MaxDelay:      30 * time.Second,
+	MaxRetryAfter: 2 * time.Minute,
+}
+
+// delay — пауза перед повтором номер attempt (с нуля): Retry-After, если
+// сервер его прислал, иначе BaseDelay·2^attempt со случайным разбросом
+// в пределах [d/2, d].
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return min(apiErr.RetryAfter, p.MaxRetryAfter)
+	}
+	d := p.BaseDelay << attempt
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable: 429 и 5xx, а также таймаут отдельной попытки (но не отмена
+// или истечение внешнего контекста).
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// Complete выполняет запрос к провайдеру с таймаутом каждой попытки
+// и повторами по политике.
+func (p RetryPolicy) Complete(ctx context.Context, provider Provider, req Request) (string, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := p.attempt(ctx, provider, req)
+		if err == nil || attempt >= p.MaxRetries || !retryable(ctx, err) {
+			return result, err
+		}
+
+		wait := p.delay(attempt, err)
+		fmt.Printf("LLM: %v, повтор через %v (%d/%d)\n", err, wait.Round(time.Millisecond), attempt+1, p.MaxRetries)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", err
+		case <-timer.C:
+		}
+	}
+}
+
+func (p RetryPolicy) attempt(ctx context.Context, provider Provider, req Request) (string, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	return provider.Complete(ctx, req)
+}
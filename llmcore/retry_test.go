@@ -0,0 +1,102 @@
+package llmcore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testPolicy = RetryPolicy{
+	Timeout:       time.Second,
+	MaxRetries:    3,
+	BaseDelay:     time.Millisecond,
+	MaxDelay:      10 * time.Millisecond,
+	MaxRetryAfter: 10 * time.Millisecond,
+}
+
+func TestRetryOnServerErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"message": {"content": "pong"}}`))
+		}
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	got, err := testPolicy.Complete(context.Background(), p, Request{})
+	if err != nil || got != "pong" || calls != 3 {
+		t.Fatalf("got %q, %v after %d calls", got, err, calls)
+	}
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "bad model", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	_, err := testPolicy.Complete(context.Background(), p, Request{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || calls != 1 {
+		t.Fatalf("got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	_, err := testPolicy.Complete(context.Background(), p, Request{})
+	if !errors.Is(err, ErrRateLimitExceeded) || calls != testPolicy.MaxRetries+1 {
+		t.Fatalf("got %v after %d calls", err, calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"soon":                          0,
+		"Wed, 01 Jan 2025 12:00:30 GMT": 30 * time.Second,
+	} {
+		header := http.Header{}
+		header.Set("Retry-After", value)
+		if got := parseRetryAfter(header, now); got != want {
+			t.Errorf("Retry-After %q: got %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxRetryAfter: time.Minute}
+	for attempt := 0; attempt < 10; attempt++ {
+		d := p.delay(attempt, errors.New("timeout"))
+		limit := min(p.BaseDelay<<attempt, p.MaxDelay)
+		if d < limit/2 || d > limit {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", attempt, d, limit/2, limit)
+		}
+	}
+	if d := p.delay(0, &APIError{StatusCode: 429, RetryAfter: 5 * time.Second}); d != 5*time.Second {
+		t.Errorf("Retry-After ignored: %v", d)
+	}
+}
@@ -0,0 +1,155 @@
+package llmcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ==========================================
+// Структурированный ответ шага формализации
+// ==========================================
+
+// JSONSchema — схема ответа для провайдеров со структурированным выводом
+// (response_format у OpenAI-совместимых API, format у Ollama). Остальные
+// провайдеры её игнорируют, а ответ разбирается через ExtractJSON.
+type JSONSchema struct {
+	Name   string
+	Schema map[string]interface{}
+}
+
+// Виды символов в словаре
+const (
+	SymbolPredicate = "predicate"
+	SymbolFunction  = "function"
+	SymbolConstant  = "constant"
+)
+
+// GlossaryEntry — описание символа формализации: что означает предикат,
+// функция или константа и сколько у неё аргументов.
+type GlossaryEntry struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Arity       int    `json:"arity"`
+	Description string `json:"description"`
+}
+
+// Formalization — ответ на ParsingPrompt: клаузы с ролями и словарь символов.
+// Glossary равен nil, если модель ответила без словаря (массив клауз).
+type Formalization struct {
+	Clauses  []ParsedClause  `json:"clauses"`
+	Glossary []GlossaryEntry `json:"glossary"`
+}
+
+// FormalizationSchema — JSON-схема Formalization для строгого режима
+// OpenAI: все поля обязательны, лишние запрещены.
+var FormalizationSchema = &JSONSchema{
+	Name: "formalization",
+	Schema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"clauses": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"clause": map[string]interface{}{"type": "string"},
+						"source": map[string]interface{}{"type": "string"},
+						"role": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"axiom", "hypothesis", "negated_conjecture"},
+						},
+					},
+					"required":             []string{"clause", "source", "role"},
+					"additionalProperties": false,
+				},
+			},
+			"glossary": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string"},
+						"kind": map[string]interface{}{
+							"type": "string",
+							"enum": []string{SymbolPredicate, SymbolFunction, SymbolConstant},
+						},
+						"arity":       map[string]interface{}{"type": "integer"},
+						"description": map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"name", "kind", "arity", "description"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"clauses", "glossary"},
+		"additionalProperties": false,
+	},
+}
+
+// ParseFormalization разбирает ответ LLM: объект Formalization или (для
+// моделей без структурированного вывода) массив клауз без словаря.
+// Markdown-блоки и текст вокруг JSON отбрасываются.
+func ParseFormalization(input string) (Formalization, error) {
+	input = ExtractJSON(input)
+	if !strings.HasPrefix(input, "{") {
+		clauses, err := ParseClauseList(input)
+		return Formalization{Clauses: clauses}, err
+	}
+
+	var result Formalization
+	if err := json.Unmarshal([]byte(input), &result); err != nil {
+		return Formalization{}, fmt.Errorf("ошибка парсинга JSON: %w", err)
+	}
+	for i, c := range result.Clauses {
+		if c.Clause == "" {
+			return Formalization{}, fmt.Errorf("элемент %d не содержит поля \"clause\"", i)
+		}
+	}
+	return result, nil
+}
+
+// ExtractJSON вырезает из ответа модели первый JSON-объект или массив:
+// убирает ограждения ```json ... ``` и пояснения до и после. Если
+// скобок нет, возвращает текст без изменений (кроме пробелов по краям).
+func ExtractJSON(input string) string {
+	s := strings.TrimSpace(input)
+	if i := strings.Index(s, "```"); i >= 0 {
+		body := s[i+3:]
+		// Пропускаем язык блока (```json)
+		if nl := strings.IndexByte(body, '\n'); nl >= 0 && !strings.ContainsAny(body[:nl], "[{") {
+			body = body[nl+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			body = body[:end]
+		}
+		s = strings.TrimSpace(body)
+	}
+
+	start := strings.IndexAny(s, "[{")
+	if start < 0 {
+		return s
+	}
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+			if depth == 0 {
+				return s[start : i+1]
+			}
+		}
+	}
+	return s[start:]
+}
@@ -0,0 +1,122 @@
+package llmcore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	for input, want := range map[string]string{
+		`["a", "b"]`:            `["a", "b"]`,
+		"```json\n[\"a\"]\n```": `["a"]`,
+		"Вот ответ:\n```\n{\"clauses\": []}\n```\nГотово.": `{"clauses": []}`,
+		"```[\"a\"]```": `["a"]`,
+		`Клаузы: ["a ] b", "c"] — это всё.`: `["a ] b", "c"]`,
+		`{"s": "\"}"} и ещё {"x": 1}`:       `{"s": "\"}"}`,
+		"без json":                          "без json",
+	} {
+		if got := ExtractJSON(input); got != want {
+			t.Errorf("ExtractJSON(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestParseFormalization(t *testing.T) {
+	f, err := ParseFormalization("```json\n" + `{"clauses": [{"clause": "Человек(Сократ)", "source": "Сократ — человек.", "role": "hypothesis"}],
+	 "glossary": [{"name": "Человек", "kind": "predicate", "arity": 1, "description": "x — человек"}]}` + "\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Clauses) != 1 || f.Clauses[0].Role != "hypothesis" || len(f.Glossary) != 1 || f.Glossary[0].Arity != 1 {
+		t.Fatalf("unexpected result: %+v", f)
+	}
+
+	// Старый формат: массив без словаря
+	f, err = ParseFormalization(`[{"clause": "Человек(Сократ)", "source": "Сократ — человек."}]`)
+	if err != nil || len(f.Clauses) != 1 || f.Glossary != nil {
+		t.Fatalf("unexpected result: %+v, %v", f, err)
+	}
+
+	if _, err := ParseFormalization(`{"clauses": [{"source": "Сократ — человек."}]}`); err == nil {
+		t.Fatal("expected error for clause without text")
+	}
+}
+
+func TestOpenAIProviderSchemaFallback(t *testing.T) {
+	var formats []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseFormat *struct {
+				Type string `json:"type"`
+			} `json:"response_format"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		formats = append(formats, body.ResponseFormat != nil)
+		if body.ResponseFormat != nil {
+			http.Error(w, `{"error": {"message": "response_format is not supported"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "[]"}}]}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOpenAI, BaseURL: server.URL, APIKey: "key"})
+	for i := 0; i < 2; i++ {
+		if _, err := p.Complete(context.Background(), Request{Schema: FormalizationSchema}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Схема отправлена один раз, дальше провайдер обходится без неё
+	if len(formats) != 3 || !formats[0] || formats[1] || formats[2] {
+		t.Fatalf("response_format sent: %v", formats)
+	}
+}
+
+func TestOpenAIProviderKeepsSchemaOnOtherErrors(t *testing.T) {
+	var formats []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResponseFormat *struct {
+				Type string `json:"type"`
+			} `json:"response_format"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		formats = append(formats, body.ResponseFormat != nil)
+		http.Error(w, `{"error": {"message": "maximum context length exceeded", "param": "messages"}}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOpenAI, BaseURL: server.URL, APIKey: "key"})
+	for i := 0; i < 2; i++ {
+		if _, err := p.Complete(context.Background(), Request{Schema: FormalizationSchema}); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+	// Ошибка не про схему: запрос не повторяется, схема не отключается
+	if len(formats) != 2 || !formats[0] || !formats[1] {
+		t.Fatalf("response_format sent: %v", formats)
+	}
+}
+
+func TestOllamaProviderSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Format map[string]interface{} `json:"format"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Format["type"] != "object" {
+			t.Errorf("schema not sent: %v", body.Format)
+		}
+		w.Write([]byte(`{"message": {"content": "{}"}}`))
+	}))
+	defer server.Close()
+
+	p, _ := NewProvider(ProviderConfig{Kind: ProviderOllama, BaseURL: server.URL, Model: "llama3"})
+	if _, err := p.Complete(context.Background(), Request{Schema: FormalizationSchema}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"neurosolver/backend"
+	"neurosolver/config"
 	"os"
 	"runtime"
 
@@ -23,7 +25,16 @@ func main() {
 		os.Setenv("GDK_BACKEND", "x11")
 	}
 
-	ln, err := net.Listen("tcp", "127.0.0.1:51115")
+	// Настройки из файла; ошибки не мешают запуску — их можно исправить в окне настроек
+	cfg, err := config.Load()
+	if err != nil {
+		log.Println(err)
+	}
+	if err := backend.ApplySettings(cfg); err != nil {
+		log.Println(err)
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.Port))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -40,6 +51,12 @@ func main() {
 
 	// API функция (Backend логика)
 	w.Bind("solveProblemAsync", backend.SolveProblemHandler(w))
+	w.Bind("manualSolveAsync", backend.ManualSolveHandler(w))
+	w.Bind("formalizeAsync", backend.FormalizeHandler(w))
+	w.Bind("proveAsync", backend.ProveHandler(w))
+	w.Bind("exportProblemAsync", backend.ExportProblemHandler(w))
+	w.Bind("getSettingsAsync", backend.GetSettingsHandler(w))
+	w.Bind("saveSettingsAsync", backend.SaveSettingsHandler(w))
 
 	w.Navigate("http://" + ln.Addr().String() + "/assets/index.html")
 
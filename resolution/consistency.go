@@ -0,0 +1,30 @@
+package resolution
+
+// ==========================================
+// Проверка непротиворечивости посылок
+// ==========================================
+
+// ConsistencyResult — результат проверки базы знаний без отрицания цели.
+type ConsistencyResult struct {
+	Consistent bool      // противоречие среди посылок не найдено
+	Complete   bool      // база насыщена полностью (иначе проверка остановлена по лимиту)
+	Conflict   []*Clause // минимальный противоречивый набор посылок (если Consistent == false)
+	Log        string
+}
+
+// CheckConsistency запускает доказатель на посылках базы знаний, пропуская
+// клаузы с ролью RoleNegatedConjecture. Если из посылок выводится □, то
+// «доказуемо» что угодно, и результат основного доказательства не имеет смысла.
+func (e *ResolutionEngine) CheckConsistency() ConsistencyResult {
+	premises, _ := e.splitGoal()
+	res := e.search(premises, nil, consistency_iterations)
+	if !res.Success {
+		return ConsistencyResult{Consistent: true, Complete: !res.LimitReached, Log: res.FullLog}
+	}
+	return ConsistencyResult{
+		Consistent: false,
+		Complete:   true,
+		Conflict:   e.minimizeCore(res.Premises()),
+		Log:        res.ShortLog,
+	}
+}
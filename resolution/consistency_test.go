@@ -0,0 +1,62 @@
+package resolution
+
+import "testing"
+
+func TestCheckConsistencyDetectsContradiction(t *testing.T) {
+	// Посылки противоречат друг другу независимо от цели.
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Птица(Пингвин)", Source: "Пингвин — птица."},
+		{Text: "¬Птица(x) ∨ Летает(x)", Source: "Все птицы летают."},
+		{Text: "¬Летает(Пингвин)", Source: "Пингвин не летает."},
+		{Text: "Человек(Сократ)", Source: "Сократ — человек."},
+	})
+
+	res := engine.CheckConsistency()
+	if res.Consistent {
+		t.Fatalf("expected inconsistency\nLog:\n%s", res.Log)
+	}
+	if len(res.Conflict) != 3 {
+		t.Fatalf("expected conflict of 3 premises, got %d", len(res.Conflict))
+	}
+	for _, c := range res.Conflict {
+		if c.Source == "Сократ — человек." {
+			t.Errorf("unrelated premise %s reported as conflicting", c)
+		}
+	}
+
+	// Цель при этом «доказывается» — именно поэтому нужна предварительная проверка.
+	engine.AddClauses([]string{"¬Смертен(Сократ)"})
+	if goal := engine.Prove(); !goal.Success {
+		t.Fatalf("expected inconsistent base to prove anything\nFullLog:\n%s", goal.FullLog)
+	}
+}
+
+func TestCheckConsistencyAcceptsConsistentBase(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"Человек(Сократ)",
+		"¬Человек(x) ∨ Смертен(x)",
+	})
+
+	res := engine.CheckConsistency()
+	if !res.Consistent || !res.Complete {
+		t.Fatalf("expected consistent saturated base, got %+v", res)
+	}
+}
+
+func TestCheckConsistencyStopsOnInfiniteSaturation(t *testing.T) {
+	// Функция Сколема порождает бесконечную цепочку резольвент.
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"Число(Ноль)",
+		"¬Число(x) ∨ Число(След(x))",
+		"¬Число(x) ∨ Больше(След(x), x)",
+		"¬Больше(x, y) ∨ ¬Больше(y, z) ∨ Больше(x, z)",
+	})
+
+	res := engine.CheckConsistency()
+	if !res.Consistent || res.Complete {
+		t.Fatalf("expected consistent but incomplete check, got Consistent=%v Complete=%v", res.Consistent, res.Complete)
+	}
+}
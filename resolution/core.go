@@ -0,0 +1,55 @@
+package resolution
+
+// ==========================================
+// Минимальное противоречивое ядро (unsat core)
+// ==========================================
+
+// MinimalCore сокращает набор начальных клауз успешного доказательства
+// до минимального противоречивого подмножества: каждая клауза по очереди
+// исключается, и если опровержение находится без неё, она не нужна.
+// Каждая проверка ограничена core_iterations; если лимит исчерпан, клауза
+// остаётся в ядре — ядро может оказаться не минимальным, но не неверным.
+func (e *ResolutionEngine) MinimalCore(result ProofResult) []*Clause {
+	if !result.Success {
+		return nil
+	}
+	return e.minimizeCore(result.Premises())
+}
+
+func (e *ResolutionEngine) minimizeCore(core []*Clause) []*Clause {
+	for i := 0; i < len(core); {
+		candidate := make([]*Clause, 0, len(core)-1)
+		candidate = append(candidate, core[:i]...)
+		candidate = append(candidate, core[i+1:]...)
+
+		res := e.search(candidate, nil, core_iterations)
+		if !res.Success {
+			// Без этой клаузы противоречие не найдено (или не найдено
+			// в пределах лимита) — она остаётся в ядре
+			i++
+			continue
+		}
+		// Новое доказательство может обходиться ещё меньшим набором клауз,
+		// поэтому продолжаем с его посылок, сохраняя уже проверенный префикс.
+		core = keepOrder(core, res.Premises())
+		if i > len(core) {
+			i = len(core)
+		}
+	}
+	return core
+}
+
+// keepOrder оставляет в core только клаузы из used, не меняя их порядок.
+func keepOrder(core, used []*Clause) []*Clause {
+	usedIDs := make(map[int]bool, len(used))
+	for _, c := range used {
+		usedIDs[c.ID] = true
+	}
+	result := make([]*Clause, 0, len(used))
+	for _, c := range core {
+		if usedIDs[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package resolution
+
+import (
+	"sort"
+	"testing"
+)
+
+func coreTexts(core []*Clause) []string {
+	texts := make([]string, len(core))
+	for i, c := range core {
+		texts[i] = c.String()
+	}
+	sort.Strings(texts)
+	return texts
+}
+
+func TestMinimalCoreDropsRedundantPremises(t *testing.T) {
+	// Два независимых пути к цели: доказательство может использовать оба правила,
+	// но для противоречия достаточно одного из них.
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Source: "Сократ — человек."},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Source: "Все люди смертны."},
+		{Text: "Философ(Сократ)", Source: "Сократ — философ."},
+		{Text: "Грек(Сократ)", Source: "Сократ — грек."},
+		{Text: "¬Смертен(Сократ)", Source: "Докажи, что Сократ смертен."},
+	})
+
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	core := engine.MinimalCore(res)
+	got := coreTexts(core)
+	want := []string{"¬Смертен(Сократ)", "¬Человек(x) ∨ Смертен(x)", "Человек(Сократ)"}
+	if len(got) != len(want) {
+		t.Fatalf("core mismatch: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("core mismatch: got %v, want %v", got, want)
+		}
+	}
+
+	for _, c := range core {
+		if c.Source == "" {
+			t.Errorf("clause [%d] %s lost its source sentence", c.ID, c)
+		}
+	}
+}
+
+func TestMinimalCoreShrinksRedundantRule(t *testing.T) {
+	// Цель выводится и напрямую, и через лишнюю цепочку А → Б → В.
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"А(Объект)",
+		"¬А(x) ∨ Б(x)",
+		"¬Б(x) ∨ В(x)",
+		"¬А(x) ∨ В(x)",
+		"¬В(Объект)",
+	})
+
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	core := engine.MinimalCore(res)
+	if len(core) != 3 {
+		t.Fatalf("expected core of 3 clauses, got %v", coreTexts(core))
+	}
+}
+
+func TestMinimalCoreOnFailure(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A)", "¬Q(A)"})
+	if core := engine.MinimalCore(engine.Prove()); core != nil {
+		t.Fatalf("expected nil core for failed proof, got %v", coreTexts(core))
+	}
+}
@@ -0,0 +1,183 @@
+package resolution
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ==========================================
+// Импорт и экспорт ground-задач в формате DIMACS CNF
+// ==========================================
+//
+// Атомы нумеруются в порядке появления. Таблица символов записывается
+// комментариями вида "c 1 Человек(Сократ)", поэтому при обратном чтении
+// пропозициональные переменные снова превращаются в литералы движка.
+// Переменные без такого комментария получают имена P1, P2...
+
+var dimacsSymbolLine = regexp.MustCompile(`^c\s+(\d+)\s+(\S.*)$`)
+
+// WriteDIMACS выгружает базу знаний в DIMACS CNF и возвращает таблицу
+// символов: symbols[i] — атом пропозициональной переменной i+1.
+// Клаузы с переменными (не ground) в DIMACS непредставимы.
+func (e *ResolutionEngine) WriteDIMACS(w io.Writer) ([]string, error) {
+	index := make(map[string]int)
+	var symbols []string
+	lines := make([]string, 0, len(e.clauses))
+
+	for _, c := range e.clauses {
+		if !isGroundClause(c) {
+			return nil, fmt.Errorf("DIMACS поддерживает только клаузы без переменных: [%d] %s", c.ID, c)
+		}
+		parts := make([]string, 0, len(c.Literals)+1)
+		for _, lit := range c.Literals {
+			atom := NewLiteral(lit.Predicate, lit.Args, false).String()
+			n, ok := index[atom]
+			if !ok {
+				symbols = append(symbols, atom)
+				n = len(symbols)
+				index[atom] = n
+			}
+			if lit.Negated {
+				n = -n
+			}
+			parts = append(parts, strconv.Itoa(n))
+		}
+		parts = append(parts, "0")
+		lines = append(lines, strings.Join(parts, " "))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "c neurosolver")
+	for i, atom := range symbols {
+		fmt.Fprintf(bw, "c %d %s\n", i+1, atom)
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", len(symbols), len(lines))
+	for _, line := range lines {
+		fmt.Fprintln(bw, line)
+	}
+	return symbols, bw.Flush()
+}
+
+func isGroundClause(c *Clause) bool {
+	for _, lit := range c.Literals {
+		for _, arg := range lit.Args {
+			if !isGroundTerm(arg) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isGroundTerm(t Term) bool {
+	if t.IsVariable() {
+		return false
+	}
+	if f, ok := t.(*Function); ok {
+		for _, a := range f.args {
+			if !isGroundTerm(a) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// LoadDIMACS читает задачу в формате DIMACS CNF и добавляет её клаузы
+// в базу знаний (с ролью RoleAxiom).
+func (e *ResolutionEngine) LoadDIMACS(r io.Reader) ([]*Clause, error) {
+	symbols := make(map[int]*Literal)
+	declaredVars, declaredClauses := -1, -1
+	var clauses [][]int
+	var current []int
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "%") {
+			// Конец задачи в файлах SATLIB
+			break
+		}
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "c"):
+			if m := dimacsSymbolLine.FindStringSubmatch(line); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				if name, args := parseLiteralString(m[2]); name != "" {
+					lit := NewLiteral(name, args, false)
+					if isGroundClause(&Clause{Literals: []*Literal{lit}}) {
+						symbols[n] = lit
+					}
+				}
+			}
+		case strings.HasPrefix(line, "p"):
+			fields := strings.Fields(line)
+			if len(fields) != 4 || fields[1] != "cnf" {
+				return nil, fmt.Errorf("DIMACS, строка %d: ожидалось \"p cnf <переменные> <клаузы>\"", lineNum)
+			}
+			var err1, err2 error
+			declaredVars, err1 = strconv.Atoi(fields[2])
+			declaredClauses, err2 = strconv.Atoi(fields[3])
+			if err1 != nil || err2 != nil || declaredVars < 0 || declaredClauses < 0 {
+				return nil, fmt.Errorf("DIMACS, строка %d: некорректный заголовок %q", lineNum, line)
+			}
+		default:
+			if declaredVars < 0 {
+				return nil, fmt.Errorf("DIMACS, строка %d: клауза до заголовка \"p cnf\"", lineNum)
+			}
+			for _, field := range strings.Fields(line) {
+				n, err := strconv.Atoi(field)
+				if err != nil {
+					return nil, fmt.Errorf("DIMACS, строка %d: некорректный литерал %q", lineNum, field)
+				}
+				if n == 0 {
+					clauses = append(clauses, current)
+					current = nil
+					continue
+				}
+				if n > declaredVars || -n > declaredVars {
+					return nil, fmt.Errorf("DIMACS, строка %d: переменная %d вне диапазона 1..%d", lineNum, n, declaredVars)
+				}
+				current = append(current, n)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения DIMACS: %w", err)
+	}
+	if len(current) > 0 {
+		clauses = append(clauses, current)
+	}
+	if declaredClauses >= 0 && len(clauses) != declaredClauses {
+		return nil, fmt.Errorf("DIMACS: в заголовке объявлено %d клауз, прочитано %d", declaredClauses, len(clauses))
+	}
+
+	added := make([]*Clause, 0, len(clauses))
+	for _, nums := range clauses {
+		literals := make([]*Literal, len(nums))
+		for i, n := range nums {
+			v := n
+			if v < 0 {
+				v = -v
+			}
+			atom, ok := symbols[v]
+			if !ok {
+				atom = NewLiteral(fmt.Sprintf("P%d", v), nil, false)
+			}
+			literals[i] = NewLiteral(atom.Predicate, atom.Args, n < 0)
+		}
+		c := NewClause(e.getNextID(), literals, "init", [2]*Clause{}, "")
+		c.Role = RoleAxiom
+		added = append(added, c)
+	}
+	e.clauses = append(e.clauses, added...)
+	e.background = nil
+	return added, nil
+}
@@ -0,0 +1,94 @@
+package resolution
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDIMACSRoundTrip(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"Человек(Сократ)",
+		"¬Человек(Сократ) ∨ Смертен(Сократ)",
+		"¬Смертен(Сократ)",
+	})
+
+	var buf bytes.Buffer
+	symbols, err := engine.WriteDIMACS(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(symbols) != 2 || symbols[0] != "Человек(Сократ)" || symbols[1] != "Смертен(Сократ)" {
+		t.Fatalf("unexpected symbol table %q", symbols)
+	}
+	out := buf.String()
+	for _, want := range []string{"c 1 Человек(Сократ)\n", "p cnf 2 3\n", "1 0\n", "-1 2 0\n", "-2 0\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	loaded := NewResolutionEngine()
+	clauses, err := loaded.LoadDIMACS(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original := engine.Clauses()
+	for i := range original {
+		if clauses[i].String() != original[i].String() {
+			t.Errorf("clause %d: got %s, want %s", i, clauses[i], original[i])
+		}
+	}
+	if res := loaded.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestWriteDIMACSRejectsVariables(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"¬Человек(x) ∨ Смертен(x)"})
+	if _, err := engine.WriteDIMACS(&bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for non-ground clause, got nil")
+	}
+}
+
+func TestLoadDIMACSBenchmark(t *testing.T) {
+	// Все четыре комбинации двух переменных запрещены — формула невыполнима.
+	src := `c simple unsat instance
+p cnf 2 4
+1 2 0
+-1 2 0
+1 -2
+0
+-1 -2 0
+%
+0
+`
+	engine := NewResolutionEngine()
+	clauses, err := engine.LoadDIMACS(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clauses) != 4 || clauses[0].String() != "P1() ∨ P2()" {
+		t.Fatalf("unexpected clauses: %v", clauses)
+	}
+	if res := engine.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestLoadDIMACSErrors(t *testing.T) {
+	cases := map[string]string{
+		"no header":    "1 2 0\n",
+		"out of range": "p cnf 1 1\n1 2 0\n",
+		"bad literal":  "p cnf 2 1\n1 x 0\n",
+		"count":        "p cnf 2 2\n1 2 0\n",
+	}
+	for name, src := range cases {
+		engine := NewResolutionEngine()
+		if _, err := engine.LoadDIMACS(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package resolution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ==========================================
+// JSON-формат обмена задачами и результатами
+// ==========================================
+
+// DocumentVersion — версия схемы ProblemDocument и ResultDocument.
+// Увеличивается при несовместимых изменениях; описание схемы — в README.
+const DocumentVersion = 1
+
+// Статусы результата в ResultDocument
+const (
+	StatusProved    = "proved"
+	StatusNotProved = "not_proved"
+	StatusTimeout   = "timeout"
+)
+
+// ProblemDocument — задача: исходный текст и её клаузы с ролями.
+type ProblemDocument struct {
+	Version int              `json:"version"`
+	Source  string           `json:"source,omitempty"`
+	Clauses []ClauseDocument `json:"clauses"`
+}
+
+// ClauseDocument — начальная клауза задачи в синтаксисе движка.
+type ClauseDocument struct {
+	ID     int    `json:"id"`
+	Text   string `json:"text"`
+	Role   Role   `json:"role"`
+	Source string `json:"source,omitempty"`
+}
+
+// ResultDocument — результат доказательства.
+type ResultDocument struct {
+	Version    int                `json:"version"`
+	Status     string             `json:"status"`
+	Proof      []ProofStep        `json:"proof,omitempty"`
+	Statistics StatisticsDocument `json:"statistics"`
+}
+
+// ProofStep — узел графа доказательства. У начальных клауз нет родителей,
+// у резольвент — два родителя и унификатор шага.
+type ProofStep struct {
+	ID           int               `json:"id"`
+	Text         string            `json:"text"`
+	Rule         string            `json:"rule"` // "input" или "resolution"
+	Role         Role              `json:"role,omitempty"`
+	Source       string            `json:"source,omitempty"`
+	Parents      []int             `json:"parents,omitempty"`
+	Substitution map[string]string `json:"substitution,omitempty"`
+}
+
+// StatisticsDocument — счётчики поиска.
+type StatisticsDocument struct {
+	Checks     int `json:"checks"`
+	Generated  int `json:"generated"`
+	ProofSteps int `json:"proof_steps"`
+}
+
+// ProblemDocument описывает текущую базу знаний движка; source — исходный
+// текст задачи на естественном языке (может быть пустым).
+func (e *ResolutionEngine) ProblemDocument(source string) ProblemDocument {
+	doc := ProblemDocument{Version: DocumentVersion, Source: source, Clauses: []ClauseDocument{}}
+	for _, c := range e.clauses {
+		doc.Clauses = append(doc.Clauses, ClauseDocument{ID: c.ID, Text: c.String(), Role: c.Role, Source: c.Source})
+	}
+	return doc
+}
+
+// LoadProblemDocument заменяет базу знаний клаузами документа. Номера клауз
+// назначаются заново, поэтому ID из документа могут не сохраниться.
+func (e *ResolutionEngine) LoadProblemDocument(doc ProblemDocument) ([]*Clause, error) {
+	if doc.Version != DocumentVersion {
+		return nil, fmt.Errorf("неподдерживаемая версия документа задачи: %d", doc.Version)
+	}
+	inputs := make([]InputClause, len(doc.Clauses))
+	for i, c := range doc.Clauses {
+		role, err := ParseRole(string(c.Role))
+		if err != nil {
+			return nil, fmt.Errorf("клауза %d: %w", c.ID, err)
+		}
+		inputs[i] = InputClause{Text: c.Text, Source: c.Source, Role: role}
+	}
+	e.ParseInput(nil)
+	return e.AddInput(inputs), nil
+}
+
+// NewResultDocument переводит результат доказательства в документ.
+func NewResultDocument(result ProofResult) ResultDocument {
+	doc := ResultDocument{
+		Version: DocumentVersion,
+		Status:  StatusNotProved,
+		Statistics: StatisticsDocument{
+			Checks:    result.Checks,
+			Generated: result.Generated,
+		},
+	}
+	switch {
+	case result.Success:
+		doc.Status = StatusProved
+	case result.LimitReached:
+		doc.Status = StatusTimeout
+	}
+	if !result.Success {
+		return doc
+	}
+
+	doc.Statistics.ProofSteps = proofSteps(result.Chain)
+	for _, c := range result.Chain {
+		step := ProofStep{ID: c.ID, Text: c.String(), Rule: "input", Role: c.Role, Source: c.Source}
+		if c.Origin == "res" {
+			step.Rule = "resolution"
+			step.Parents = []int{c.Parents[0].ID, c.Parents[1].ID}
+			if len(c.Subst) > 0 {
+				step.Substitution = make(map[string]string, len(c.Subst))
+				for name, t := range c.Subst {
+					step.Substitution[name] = t.String()
+				}
+			}
+		}
+		doc.Proof = append(doc.Proof, step)
+	}
+	return doc
+}
+
+// WriteDocument записывает документ в JSON с отступами.
+func WriteDocument(w io.Writer, doc interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ReadProblemDocument читает ProblemDocument и проверяет версию схемы.
+func ReadProblemDocument(r io.Reader) (ProblemDocument, error) {
+	var doc ProblemDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("документ задачи: %w", err)
+	}
+	if doc.Version != DocumentVersion {
+		return doc, fmt.Errorf("неподдерживаемая версия документа задачи: %d", doc.Version)
+	}
+	return doc, nil
+}
+
+// ReadResultDocument читает ResultDocument и проверяет версию схемы.
+func ReadResultDocument(r io.Reader) (ResultDocument, error) {
+	var doc ResultDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return doc, fmt.Errorf("документ результата: %w", err)
+	}
+	if doc.Version != DocumentVersion {
+		return doc, fmt.Errorf("неподдерживаемая версия документа результата: %d", doc.Version)
+	}
+	switch doc.Status {
+	case StatusProved, StatusNotProved, StatusTimeout:
+	default:
+		return doc, fmt.Errorf("неизвестный статус результата: %q", doc.Status)
+	}
+	return doc, nil
+}
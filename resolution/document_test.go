@@ -0,0 +1,67 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProblemDocumentRoundTrip(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Source: "Сократ — человек.", Role: RoleHypothesis},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Source: "Все люди смертны.", Role: RoleAxiom},
+		{Text: "¬Смертен(x)", Source: "Кто смертен?", Role: RoleNegatedConjecture},
+	})
+
+	var buf strings.Builder
+	if err := WriteDocument(&buf, engine.ProblemDocument("Все люди смертны. Сократ — человек.")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"version": 1`, `"role": "axiom"`, `"source": "Кто смертен?"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("document does not contain %q:\n%s", want, buf.String())
+		}
+	}
+
+	doc, err := ReadProblemDocument(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewResolutionEngine()
+	clauses, err := loaded.LoadProblemDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clauses) != 3 || !clauses[2].IsGoal() || clauses[0].Role != RoleHypothesis {
+		t.Fatalf("unexpected clauses after load: %v", clauses)
+	}
+
+	result := NewResultDocument(loaded.Prove())
+	if result.Status != StatusProved {
+		t.Fatalf("expected status %q, got %q", StatusProved, result.Status)
+	}
+	last := result.Proof[len(result.Proof)-1]
+	if last.Text != "□" || last.Rule != "resolution" || len(last.Parents) != 2 {
+		t.Errorf("unexpected last step: %+v", last)
+	}
+	if result.Statistics.ProofSteps != 2 || result.Statistics.Checks == 0 {
+		t.Errorf("unexpected statistics: %+v", result.Statistics)
+	}
+
+	buf.Reset()
+	if err := WriteDocument(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadResultDocument(strings.NewReader(buf.String())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocumentVersionMismatch(t *testing.T) {
+	if _, err := ReadProblemDocument(strings.NewReader(`{"version": 2, "clauses": []}`)); err == nil {
+		t.Error("expected error for unsupported problem version")
+	}
+	if _, err := ReadResultDocument(strings.NewReader(`{"version": 1, "status": "maybe"}`)); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
@@ -0,0 +1,241 @@
+package resolution
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ==========================================
+// Объяснение доказательства по шаблонам (без LLM)
+// ==========================================
+
+// DescribeClause пересказывает клаузу по-русски по тем же правилам, что
+// и ExplanationPrompt: ¬A ∨ B читается как импликация «если A, то B»,
+// одиночный литерал — как факт или отрицание.
+func DescribeClause(c *Clause) string {
+	if c.IsEmpty() {
+		return "противоречие"
+	}
+	var pos, neg []*Literal
+	for _, l := range c.Literals {
+		if l.Negated {
+			neg = append(neg, l)
+		} else {
+			pos = append(pos, l)
+		}
+	}
+
+	var s string
+	switch {
+	case len(c.Literals) == 1:
+		s = describeLiteral(c.Literals[0])
+	case len(neg) > 0 && len(pos) > 0:
+		s = fmt.Sprintf("если %s, то %s", joinLiterals(neg, " и ", true), joinLiterals(pos, " или ", false))
+	case len(neg) > 0:
+		s = "не может быть одновременно, что " + joinLiterals(neg, " и ", true)
+	default:
+		s = "верно хотя бы одно: " + joinLiterals(pos, " или ", false)
+	}
+
+	if vars := sortedVariables(c); len(vars) > 0 && len(c.Literals) == 1 {
+		s = fmt.Sprintf("для любого %s: %s", strings.Join(vars, ", "), s)
+	}
+	return s
+}
+
+// describeLiteral: P(a) — «a является P», P(a, b) — «выполняется P(a, b)».
+func describeLiteral(l *Literal) string {
+	args := make([]string, len(l.Args))
+	for i, a := range l.Args {
+		args[i] = a.String()
+	}
+	switch len(args) {
+	case 0:
+		if l.Negated {
+			return "неверно, что " + l.Predicate
+		}
+		return "верно " + l.Predicate
+	case 1:
+		if l.Negated {
+			return fmt.Sprintf("%s не является %s", args[0], l.Predicate)
+		}
+		return fmt.Sprintf("%s является %s", args[0], l.Predicate)
+	}
+	atom := fmt.Sprintf("%s(%s)", l.Predicate, strings.Join(args, ", "))
+	if l.Negated {
+		return "не выполняется " + atom
+	}
+	return "выполняется " + atom
+}
+
+// joinLiterals перечисляет литералы; positive — описывать атомы без отрицания
+// (для условия импликации ¬A ∨ B).
+func joinLiterals(lits []*Literal, sep string, positive bool) string {
+	parts := make([]string, len(lits))
+	for i, l := range lits {
+		if positive && l.Negated {
+			l = l.Negate()
+		}
+		parts[i] = describeLiteral(l)
+	}
+	return strings.Join(parts, sep)
+}
+
+func sortedVariables(c *Clause) []string {
+	vars := make([]string, 0)
+	for name := range clauseVariables(c) {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// ExplainProof строит объяснение доказательства одним абзацем: посылки,
+// допущение от противного, шаги резолюции с подстановками и итоговое
+// противоречие. Если доказательство не найдено, объясняет почему
+// (см. explainFailure).
+func ExplainProof(result ProofResult) string {
+	if !result.Success {
+		return explainFailure(result)
+	}
+
+	var sentences []string
+	hasGoal := false
+	var premises, goals []string
+	for _, c := range result.Chain {
+		if c.Origin != "init" {
+			continue
+		}
+		if c.IsGoal() {
+			hasGoal = true
+			goals = append(goals, "«"+DescribeClause(c)+"»")
+		} else {
+			premises = append(premises, "«"+DescribeClause(c)+"»")
+		}
+	}
+	if hasGoal {
+		sentences = append(sentences, "Пойдём от противного.")
+	}
+	if len(premises) > 0 {
+		sentences = append(sentences, "Нам известно: "+strings.Join(premises, "; ")+".")
+	}
+	if hasGoal {
+		sentences = append(sentences, "Предположим обратное доказываемому: "+strings.Join(goals, "; ")+".")
+	}
+
+	step := 0
+	for _, c := range result.Chain {
+		if c.Origin != "res" {
+			continue
+		}
+		step++
+		connective := "Затем"
+		if step == 1 {
+			connective = "Сначала"
+		}
+		a, b := DescribeClause(c.Parents[0]), DescribeClause(c.Parents[1])
+
+		if c.IsEmpty() {
+			sentences = append(sentences, fmt.Sprintf(
+				"%s видим, что утверждения «%s» и «%s» прямо противоречат друг другу.",
+				connective, a, b))
+			continue
+		}
+		sentence := fmt.Sprintf("%s совместим «%s» и «%s»", connective, a, b)
+		if len(c.Subst) > 0 {
+			sentence += ", применив правило к конкретному объекту (" + describeSubst(c.Subst) + ")"
+		}
+		sentence += fmt.Sprintf(": отсюда следует, что %s.", DescribeClause(c))
+		sentences = append(sentences, sentence)
+	}
+
+	if hasGoal {
+		sentences = append(sentences, "Мы пришли к противоречию, значит, наше предположение было неверным, и доказываемое утверждение истинно.")
+	} else {
+		sentences = append(sentences, "Мы пришли к противоречию, значит, сами условия задачи несовместны.")
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describeSubst: {Сократ/x} — «Сократ вместо x».
+func describeSubst(subst Theta) string {
+	parts := make([]string, 0, len(subst))
+	for name, t := range subst {
+		parts = append(parts, fmt.Sprintf("%s вместо %s", t.String(), name))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// explainFailure объясняет неудачу по AnalyzeFailure: контрмодель, если
+// она найдена, известные факты и варианты недостающих посылок. Что цель не
+// следует из условий, утверждается только при найденной контрмодели:
+// движок не переименовывает переменные и ищет с опорным множеством, поэтому
+// насыщение без □ не исключает доказательства.
+func explainFailure(result ProofResult) string {
+	a := AnalyzeFailure(result)
+	var sentences []string
+	switch {
+	case a.HasModel && !result.LimitReached:
+		sentences = append(sentences, "Доказательство не найдено: из условий задачи доказываемое утверждение не следует.")
+	case a.HasModel:
+		sentences = append(sentences, "Поиск остановлен по лимиту итераций, но доказательства и не может быть: утверждение не следует из условий.")
+	case !result.LimitReached:
+		sentences = append(sentences, "Движок не нашёл доказательства: перебор закончился без противоречия. "+
+			"Поиск движка неполный, поэтому это ещё не значит, что утверждение не следует из условий.")
+	default:
+		sentences = append(sentences, "Доказательство не найдено: поиск остановлен по лимиту итераций. "+
+			"Возможно, доказательство существует, но оно длиннее допустимого, — попробуйте увеличить лимит итераций в настройках.")
+	}
+
+	if a.HasModel {
+		if len(a.CounterModel) == 0 {
+			sentences = append(sentences, "Все условия выполняются, а доказываемое утверждение ложно, если ни один из упомянутых фактов не верен.")
+		} else {
+			sentences = append(sentences, "Все условия выполняются, а доказываемое утверждение ложно, например, если верно только следующее: "+
+				describeLiterals(a.CounterModel, "; ")+", — а всё остальное неверно.")
+		}
+	} else if len(a.Facts) > 0 {
+		sentences = append(sentences, "Из условий известны такие факты: "+describeLiterals(a.Facts, "; ")+".")
+	}
+
+	switch {
+	case len(a.Missing) > 0:
+		options := make([]string, len(a.Missing))
+		for i, m := range a.Missing {
+			options[i] = describeLiterals(m, " и ")
+		}
+		lead := "Чтобы утверждение следовало из условий, не хватает, например, посылки "
+		if !a.HasModel {
+			lead = "Если же доказательства нет, возможно, в условиях не хватает посылки "
+		}
+		sentences = append(sentences, lead+strings.Join(options, " или ")+".")
+	case a.HasModel && hasGoal(result.Clauses):
+		sentences = append(sentences, "Ни одно правило из условий не ведёт к доказываемому утверждению: похоже, не хватает правила, которое связывает его с условиями задачи.")
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describeLiterals перечисляет литералы в кавычках; у литералов с
+// переменными поясняется, что подойдёт любой объект.
+func describeLiterals(lits []*Literal, sep string) string {
+	parts := make([]string, len(lits))
+	for i, l := range lits {
+		vars := sortedVariables(&Clause{Literals: []*Literal{l}})
+		parts[i] = "«" + describeLiteral(l) + "»"
+		if len(vars) > 0 {
+			parts[i] += " (для какого-нибудь " + strings.Join(vars, ", ") + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func hasGoal(clauses []*Clause) bool {
+	for _, c := range clauses {
+		if c.Origin == "init" && c.IsGoal() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribeClause(t *testing.T) {
+	engine := NewResolutionEngine()
+	clauses := engine.AddClauses([]string{
+		"¬Человек(x) ∨ Смертен(x)",
+		"Человек(Сократ)",
+		"¬Смертен(Сократ)",
+		"Любит(x, Мария)",
+		"¬Птица(x) ∨ ¬Рыба(x)",
+	})
+	for i, want := range []string{
+		"если x является Человек, то x является Смертен",
+		"Сократ является Человек",
+		"Сократ не является Смертен",
+		"для любого x: выполняется Любит(x, Мария)",
+		"не может быть одновременно, что x является Птица и x является Рыба",
+	} {
+		if got := DescribeClause(clauses[i]); got != want {
+			t.Errorf("clause %s: got %q, want %q", clauses[i], got, want)
+		}
+	}
+}
+
+func TestExplainProof(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	out := ExplainProof(res)
+	for _, want := range []string{
+		"Пойдём от противного.",
+		"Предположим обратное доказываемому: «Сократ не является Смертен».",
+		"применив правило к конкретному объекту (Сократ вместо x): отсюда следует, что Сократ не является Человек.",
+		"наше предположение было неверным",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explanation does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExplainFailure(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "Человек(Платон)", Role: RoleHypothesis},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if res.Success || res.LimitReached {
+		t.Fatalf("expected saturation without proof\nFullLog:\n%s", res.FullLog)
+	}
+
+	out := ExplainProof(res)
+	for _, want := range []string{
+		"доказываемое утверждение не следует",
+		"если верно только следующее: «Платон является Человек»; «Платон является Смертен»",
+		"не хватает, например, посылки «Сократ является Человек»",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explanation does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExplainFailureWithoutModel(t *testing.T) {
+	// Цель следует из условий, но движок без переименования переменных
+	// не унифицирует Любит(x, Мать(x)) с Любит(Иван, x)
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Любит(x, Мать(x))", Role: RoleAxiom},
+		{Text: "¬Любит(Иван, x)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if res.Success || res.LimitReached {
+		t.Fatalf("expected saturation without proof\nFullLog:\n%s", res.FullLog)
+	}
+
+	for name, out := range map[string]string{"ExplainProof": ExplainProof(res), "FormatFailure": FormatFailure(res)} {
+		if !strings.Contains(out, "не нашёл доказательства") {
+			t.Errorf("%s must say that no proof was found:\n%s", name, out)
+		}
+		if strings.Contains(out, "— утверждение не следует") || strings.Contains(out, "доказываемое утверждение не следует") ||
+			strings.Contains(strings.ToLower(out), "ни одно правило") {
+			t.Errorf("%s must not claim non-entailment without a counter-model:\n%s", name, out)
+		}
+	}
+}
+
+func TestExplainTimeout(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.MaxIterations = 100
+	engine.AddInput([]InputClause{
+		{Text: "¬Ч(Ф(x)) ∨ Ч(x)", Role: RoleAxiom},
+		{Text: "Ч(А)", Role: RoleHypothesis},
+		{Text: "¬Ч(Б)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if !res.LimitReached {
+		t.Fatalf("expected the iteration limit\nFullLog:\n%s", res.FullLog)
+	}
+
+	out := ExplainProof(res)
+	for _, want := range []string{
+		"поиск остановлен по лимиту итераций",
+		"увеличить лимит итераций",
+		"Если же доказательства нет, возможно, в условиях не хватает посылки",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("explanation does not contain %q:\n%s", want, out)
+		}
+	}
+}
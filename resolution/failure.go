@@ -0,0 +1,256 @@
+package resolution
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// ==========================================
+// Разбор неудачного поиска доказательства
+// ==========================================
+
+// maxModelAtoms ограничивает перебор интерпретаций при поиске контрмодели
+const maxModelAtoms = 16
+
+// FailureAnalysis — что удалось узнать, когда □ не найдена.
+type FailureAnalysis struct {
+	// Facts — известные факты: положительные литералы единичных
+	// клауз без переменных (начальных и выведенных).
+	Facts []*Literal
+	// Missing — варианты недостающих посылок: у каждой клаузы, выведенной
+	// из отрицания цели, отрицания её литералов вместе дают □. Короткие
+	// и более глубокие (ближе к фактам) варианты идут первыми.
+	Missing [][]*Literal
+	// CounterModel — истинные атомы интерпретации, в которой верны все
+	// начальные клаузы (включая отрицание цели), остальные атомы ложны.
+	// Ищется только для задач без функций с небольшим числом атомов.
+	CounterModel []*Literal
+	HasModel     bool
+}
+
+// maxMissing — сколько вариантов недостающих посылок предлагать
+const maxMissing = 3
+
+// AnalyzeFailure разбирает клаузы неудачного поиска (ProofResult.Clauses).
+func AnalyzeFailure(result ProofResult) FailureAnalysis {
+	var a FailureAnalysis
+	if result.Success {
+		return a
+	}
+
+	var initial []*Clause
+	seenFacts := make(map[string]bool)
+	fromGoal := make(map[int]bool)
+	var candidates []*Clause
+	for _, c := range result.Clauses {
+		if c.Origin == "init" {
+			initial = append(initial, c)
+			fromGoal[c.ID] = c.IsGoal()
+		} else if fromGoal[c.Parents[0].ID] || fromGoal[c.Parents[1].ID] {
+			// Кандидаты — только выведенные клаузы: сама цель как
+			// «недостающая посылка» ничего не объясняет
+			fromGoal[c.ID] = true
+			candidates = append(candidates, c)
+		}
+		if len(c.Literals) == 1 && !c.Literals[0].Negated && len(clauseVariables(c)) == 0 {
+			if key := c.Literals[0].String(); !seenFacts[key] {
+				seenFacts[key] = true
+				a.Facts = append(a.Facts, c.Literals[0])
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if len(candidates[i].Literals) != len(candidates[j].Literals) {
+			return len(candidates[i].Literals) < len(candidates[j].Literals)
+		}
+		return candidates[i].ID > candidates[j].ID
+	})
+	seenMissing := make(map[string]bool)
+	for _, c := range candidates {
+		if len(a.Missing) == maxMissing {
+			break
+		}
+		lits := make([]*Literal, len(c.Literals))
+		keys := make([]string, len(c.Literals))
+		for i, l := range c.Literals {
+			lits[i] = l.Negate()
+			keys[i] = lits[i].String()
+		}
+		sort.Strings(keys)
+		if key := strings.Join(keys, " ∧ "); !seenMissing[key] {
+			seenMissing[key] = true
+			a.Missing = append(a.Missing, lits)
+		}
+	}
+
+	a.CounterModel, a.HasModel = findCounterModel(initial)
+	return a
+}
+
+// findCounterModel подставляет в клаузы все константы задачи вместо
+// переменных и перебирает интерпретации атомов, начиная с наименьших.
+// Для задач без функций это полный поиск модели: найденная модель
+// доказывает, что цель не следует из посылок.
+func findCounterModel(clauses []*Clause) ([]*Literal, bool) {
+	var constants []Term
+	seenConst := make(map[string]bool)
+	for _, c := range clauses {
+		for _, l := range c.Literals {
+			for _, t := range l.Args {
+				switch t.(type) {
+				case *Function:
+					return nil, false
+				case *Constant:
+					if !seenConst[t.Name()] {
+						seenConst[t.Name()] = true
+						constants = append(constants, t)
+					}
+				}
+			}
+		}
+	}
+
+	// Основные примеры клауз: литерал — номер атома со знаком
+	type groundLiteral struct {
+		atom    int
+		negated bool
+	}
+	var atoms []*Literal
+	atomIndex := make(map[string]int)
+	var ground [][]groundLiteral
+	for _, c := range clauses {
+		vars := sortedVariables(c)
+		if len(vars) > 0 && len(constants) == 0 {
+			return nil, false
+		}
+		// Перебор всех подстановок констант вместо переменных
+		choice := make([]int, len(vars))
+		for {
+			env := make(map[string]Term, len(vars))
+			for i, v := range vars {
+				env[v] = constants[choice[i]]
+			}
+			var gc []groundLiteral
+			for _, l := range c.Literals {
+				args := make([]Term, len(l.Args))
+				for i, t := range l.Args {
+					args[i] = substituteTerm(t, env)
+				}
+				atom := NewLiteral(l.Predicate, args, false)
+				idx, ok := atomIndex[atom.String()]
+				if !ok {
+					if len(atoms) == maxModelAtoms {
+						return nil, false
+					}
+					idx = len(atoms)
+					atomIndex[atom.String()] = idx
+					atoms = append(atoms, atom)
+				}
+				gc = append(gc, groundLiteral{atom: idx, negated: l.Negated})
+			}
+			ground = append(ground, gc)
+
+			k := len(choice) - 1
+			for k >= 0 && choice[k] == len(constants)-1 {
+				choice[k] = 0
+				k--
+			}
+			if k < 0 {
+				break
+			}
+			choice[k]++
+		}
+	}
+
+	masks := make([]uint32, 1<<len(atoms))
+	for i := range masks {
+		masks[i] = uint32(i)
+	}
+	sort.SliceStable(masks, func(i, j int) bool { return bits.OnesCount32(masks[i]) < bits.OnesCount32(masks[j]) })
+
+	for _, mask := range masks {
+		satisfied := true
+		for _, gc := range ground {
+			clauseTrue := false
+			for _, gl := range gc {
+				if (mask&(1<<gl.atom) != 0) != gl.negated {
+					clauseTrue = true
+					break
+				}
+			}
+			if !clauseTrue {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			model := make([]*Literal, 0)
+			for i, atom := range atoms {
+				if mask&(1<<i) != 0 {
+					model = append(model, atom)
+				}
+			}
+			return model, true
+		}
+	}
+	return nil, false
+}
+
+// FormatFailure описывает неудачный поиск для LLM: статус, начальные
+// клаузы, известные факты, контрмодель и недостающие посылки.
+func FormatFailure(result ProofResult) string {
+	a := AnalyzeFailure(result)
+	var b strings.Builder
+	switch {
+	case result.LimitReached:
+		fmt.Fprintf(&b, "Результат: поиск остановлен по лимиту итераций (%d проверок), □ не получена.\n", result.Checks)
+	case a.HasModel:
+		b.WriteString("Результат: перебор закончен, □ не получена, найдена контрмодель — утверждение не следует из условий.\n")
+	default:
+		b.WriteString("Результат: перебор закончен, □ не получена — движок не нашёл доказательства. " +
+			"Поиск неполный, поэтому это не значит, что утверждение не следует из условий.\n")
+	}
+
+	b.WriteString("\nНачальные клаузы:\n")
+	for _, c := range result.Clauses {
+		if c.Origin != "init" {
+			continue
+		}
+		fmt.Fprintf(&b, "  [%d] %s", c.ID, c.String())
+		if c.IsGoal() {
+			b.WriteString(" (отрицание цели)")
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "\nИзвестные факты: %s\n", joinOrNone(a.Facts))
+	if a.HasModel {
+		fmt.Fprintf(&b, "Контрмодель (истинны только эти атомы, посылки верны, цель ложна): %s\n", joinOrNone(a.CounterModel))
+	}
+	b.WriteString("Недостающие посылки (любой вариант сделал бы цель выводимой):")
+	switch {
+	case len(a.Missing) > 0:
+	case a.HasModel:
+		b.WriteString(" нет — ни одно правило не связывает условия с целью")
+	default:
+		b.WriteString(" не найдены")
+	}
+	for _, m := range a.Missing {
+		b.WriteString("\n  - " + joinOrNone(m))
+	}
+	return b.String()
+}
+
+func joinOrNone(lits []*Literal) string {
+	if len(lits) == 0 {
+		return "нет"
+	}
+	parts := make([]string, len(lits))
+	for i, l := range lits {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, ", ")
+}
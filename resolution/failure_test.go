@@ -0,0 +1,39 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeFailure(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "¬Человек(x) ∨ ¬Грек(x) ∨ Философ(x)", Role: RoleAxiom},
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Философ(Сократ)", Role: RoleNegatedConjecture},
+	})
+	a := AnalyzeFailure(engine.Prove())
+
+	if got := joinOrNone(a.Facts); got != "Человек(Сократ)" {
+		t.Errorf("facts: got %s", got)
+	}
+	var missing []string
+	for _, m := range a.Missing {
+		missing = append(missing, joinOrNone(m))
+	}
+	// Сначала более короткие варианты
+	if want := "Грек(Сократ); Грек(Сократ), Человек(Сократ)"; strings.Join(missing, "; ") != want {
+		t.Errorf("missing: got %q, want %q", strings.Join(missing, "; "), want)
+	}
+	if !a.HasModel || joinOrNone(a.CounterModel) != "Человек(Сократ)" {
+		t.Errorf("counter-model: got %v %s", a.HasModel, joinOrNone(a.CounterModel))
+	}
+}
+
+func TestCounterModelRequiresFunctionFreeProblem(t *testing.T) {
+	engine := NewResolutionEngine()
+	clauses := engine.AddClauses([]string{"Больше(Ск(x), x)"})
+	if _, ok := findCounterModel(clauses); ok {
+		t.Fatal("counter-model search must skip problems with functions")
+	}
+}
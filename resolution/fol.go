@@ -0,0 +1,384 @@
+package resolution
+
+import "fmt"
+
+// ==========================================
+// Формулы логики первого порядка и приведение к КНФ
+// ==========================================
+
+type formulaOp int
+
+const (
+	opAtom formulaOp = iota
+	opTrue
+	opFalse
+	opNot
+	opAnd
+	opOr
+	opImplies
+	opIff
+	opForall
+	opExists
+)
+
+// formula — узел дерева формулы. Для opAtom заполнены pred/args,
+// для связок — sub, для кванторов — vars и sub[0].
+type formula struct {
+	op   formulaOp
+	pred string
+	args []Term
+	sub  []*formula
+	vars []string
+}
+
+func atomFormula(pred string, args []Term) *formula {
+	return &formula{op: opAtom, pred: pred, args: args}
+}
+
+func notFormula(f *formula) *formula { return &formula{op: opNot, sub: []*formula{f}} }
+
+func binaryFormula(op formulaOp, l, r *formula) *formula {
+	return &formula{op: op, sub: []*formula{l, r}}
+}
+
+func quantFormula(op formulaOp, vars []string, body *formula) *formula {
+	return &formula{op: op, vars: vars, sub: []*formula{body}}
+}
+
+// clausifier приводит формулы к набору клауз: устраняет импликации,
+// проносит отрицания, скулемизирует кванторы существования и раскрывает
+// дистрибутивность. Скулемовские символы получают префиксы «Ск» и «Конст»,
+// как и в ответах LLM.
+type clausifier struct {
+	symbols   map[string]bool // уже занятые имена функций и констант
+	skolemN   int
+	variableN int
+}
+
+func newClausifier() *clausifier {
+	return &clausifier{symbols: make(map[string]bool)}
+}
+
+// reserve запоминает имена функций и констант формулы, чтобы скулемовские
+// символы с ними не совпали.
+func (cl *clausifier) reserve(f *formula) {
+	var walkTerm func(t Term)
+	walkTerm = func(t Term) {
+		switch v := t.(type) {
+		case *Constant:
+			cl.symbols[v.name] = true
+		case *Function:
+			cl.symbols[v.name] = true
+			for _, a := range v.args {
+				walkTerm(a)
+			}
+		}
+	}
+	if f.op == opAtom {
+		for _, a := range f.args {
+			walkTerm(a)
+		}
+	}
+	for _, s := range f.sub {
+		cl.reserve(s)
+	}
+}
+
+// clausify возвращает клаузы формулы в виде списков литералов.
+// Свободные переменные считаются связанными квантором всеобщности.
+func (cl *clausifier) clausify(f *formula) [][]*Literal {
+	f = nnf(eliminateImplications(universalClosure(f)), false)
+	f = cl.skolemize(f, make(map[string]Term), nil)
+	var result [][]*Literal
+	for _, lits := range cnf(f) {
+		if isTautology(lits) {
+			continue
+		}
+		result = append(result, renameClauseVars(lits))
+	}
+	return result
+}
+
+func eliminateImplications(f *formula) *formula {
+	switch f.op {
+	case opAtom, opTrue, opFalse:
+		return f
+	case opImplies:
+		l, r := eliminateImplications(f.sub[0]), eliminateImplications(f.sub[1])
+		return binaryFormula(opOr, notFormula(l), r)
+	case opIff:
+		l, r := eliminateImplications(f.sub[0]), eliminateImplications(f.sub[1])
+		return binaryFormula(opAnd,
+			binaryFormula(opOr, notFormula(l), r),
+			binaryFormula(opOr, l, notFormula(r)))
+	}
+	sub := make([]*formula, len(f.sub))
+	for i, s := range f.sub {
+		sub[i] = eliminateImplications(s)
+	}
+	return &formula{op: f.op, pred: f.pred, args: f.args, sub: sub, vars: f.vars}
+}
+
+// nnf проносит отрицания до атомов (негативная нормальная форма).
+func nnf(f *formula, negate bool) *formula {
+	switch f.op {
+	case opAtom:
+		if negate {
+			return notFormula(f)
+		}
+		return f
+	case opTrue, opFalse:
+		if negate == (f.op == opTrue) {
+			return &formula{op: opFalse}
+		}
+		return &formula{op: opTrue}
+	case opNot:
+		return nnf(f.sub[0], !negate)
+	case opAnd, opOr:
+		op := f.op
+		if negate {
+			op = dualOp(f.op)
+		}
+		sub := make([]*formula, len(f.sub))
+		for i, s := range f.sub {
+			sub[i] = nnf(s, negate)
+		}
+		return &formula{op: op, sub: sub}
+	case opForall, opExists:
+		op := f.op
+		if negate {
+			op = dualOp(f.op)
+		}
+		return quantFormula(op, f.vars, nnf(f.sub[0], negate))
+	}
+	panic(fmt.Sprintf("nnf: неожиданная связка %d", f.op))
+}
+
+// dualOp возвращает двойственную связку (законы де Моргана и смена кванторов).
+func dualOp(op formulaOp) formulaOp {
+	switch op {
+	case opAnd:
+		return opOr
+	case opOr:
+		return opAnd
+	case opForall:
+		return opExists
+	case opExists:
+		return opForall
+	}
+	return op
+}
+
+// universalClosure связывает свободные переменные формулы квантором ∀.
+func universalClosure(f *formula) *formula {
+	var free []string
+	seen := make(map[string]bool)
+	var walkTerm func(t Term, bound map[string]bool)
+	walkTerm = func(t Term, bound map[string]bool) {
+		switch v := t.(type) {
+		case *Variable:
+			if !bound[v.name] && !seen[v.name] {
+				seen[v.name] = true
+				free = append(free, v.name)
+			}
+		case *Function:
+			for _, a := range v.args {
+				walkTerm(a, bound)
+			}
+		}
+	}
+	var walk func(g *formula, bound map[string]bool)
+	walk = func(g *formula, bound map[string]bool) {
+		if g.op == opForall || g.op == opExists {
+			inner := make(map[string]bool, len(bound)+len(g.vars))
+			for k := range bound {
+				inner[k] = true
+			}
+			for _, v := range g.vars {
+				inner[v] = true
+			}
+			bound = inner
+		}
+		for _, a := range g.args {
+			walkTerm(a, bound)
+		}
+		for _, s := range g.sub {
+			walk(s, bound)
+		}
+	}
+	walk(f, map[string]bool{})
+	if len(free) == 0 {
+		return f
+	}
+	return quantFormula(opForall, free, f)
+}
+
+// skolemize переименовывает связанные переменные в уникальные и заменяет
+// переменные под ∃ на скулемовские функции от переменных ∀ в области видимости.
+func (cl *clausifier) skolemize(f *formula, env map[string]Term, universals []Term) *formula {
+	switch f.op {
+	case opTrue, opFalse:
+		return f
+	case opAtom:
+		args := make([]Term, len(f.args))
+		for i, a := range f.args {
+			args[i] = substituteTerm(a, env)
+		}
+		return atomFormula(f.pred, args)
+	case opNot:
+		return notFormula(cl.skolemize(f.sub[0], env, universals))
+	case opAnd, opOr:
+		sub := make([]*formula, len(f.sub))
+		for i, s := range f.sub {
+			sub[i] = cl.skolemize(s, env, universals)
+		}
+		return &formula{op: f.op, sub: sub}
+	case opForall, opExists:
+		inner := make(map[string]Term, len(env)+len(f.vars))
+		for k, v := range env {
+			inner[k] = v
+		}
+		scope := universals
+		for _, name := range f.vars {
+			if f.op == opForall {
+				cl.variableN++
+				v := NewVariable(fmt.Sprintf("_%d", cl.variableN))
+				inner[name] = v
+				scope = append(scope[:len(scope):len(scope)], v)
+			} else {
+				inner[name] = cl.skolemTerm(universals)
+			}
+		}
+		return cl.skolemize(f.sub[0], inner, scope)
+	}
+	panic(fmt.Sprintf("skolemize: неожиданная связка %d", f.op))
+}
+
+func (cl *clausifier) skolemTerm(universals []Term) Term {
+	for {
+		cl.skolemN++
+		var name string
+		if len(universals) == 0 {
+			name = fmt.Sprintf("Конст%d", cl.skolemN)
+		} else {
+			name = fmt.Sprintf("Ск%d", cl.skolemN)
+		}
+		if cl.symbols[name] {
+			continue
+		}
+		cl.symbols[name] = true
+		if len(universals) == 0 {
+			return NewConstant(name)
+		}
+		args := make([]Term, len(universals))
+		copy(args, universals)
+		return NewFunction(name, args)
+	}
+}
+
+// substituteTerm заменяет переменные терма по env (без рекурсии по значениям).
+func substituteTerm(t Term, env map[string]Term) Term {
+	switch v := t.(type) {
+	case *Variable:
+		if r, ok := env[v.name]; ok {
+			return r
+		}
+		return v
+	case *Function:
+		args := make([]Term, len(v.args))
+		for i, a := range v.args {
+			args[i] = substituteTerm(a, env)
+		}
+		return NewFunction(v.name, args)
+	}
+	return t
+}
+
+// cnf раскрывает дистрибутивность. Пустой список клауз — истина,
+// список из одной пустой клаузы — ложь.
+func cnf(f *formula) [][]*Literal {
+	switch f.op {
+	case opTrue:
+		return [][]*Literal{}
+	case opFalse:
+		return [][]*Literal{{}}
+	case opAtom:
+		return [][]*Literal{{NewLiteral(f.pred, f.args, false)}}
+	case opNot:
+		a := f.sub[0]
+		return [][]*Literal{{NewLiteral(a.pred, a.args, true)}}
+	case opAnd:
+		var result [][]*Literal
+		for _, s := range f.sub {
+			result = append(result, cnf(s)...)
+		}
+		return result
+	case opOr:
+		result := [][]*Literal{{}}
+		for _, s := range f.sub {
+			var next [][]*Literal
+			for _, left := range result {
+				for _, right := range cnf(s) {
+					merged := make([]*Literal, 0, len(left)+len(right))
+					merged = append(merged, left...)
+					merged = append(merged, right...)
+					next = append(next, merged)
+				}
+			}
+			result = next
+		}
+		return result
+	}
+	panic(fmt.Sprintf("cnf: неожиданная связка %d", f.op))
+}
+
+func isTautology(lits []*Literal) bool {
+	for i, a := range lits {
+		for _, b := range lits[i+1:] {
+			if a.Negated != b.Negated && a.Equal(b.Negate()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Имена переменных в текстовом синтаксисе движка — одна строчная буква.
+// Константы таких имён не получают: LoadTPTP переименовывает
+// однобуквенные строчные константы, поэтому переменные не совпадут с ними.
+const clauseVarNames = "xyzuvwabcdefghijklmnopqrstабвгдежзийклмнопрстуфхцчшщэюя"
+
+// renameClauseVars даёт переменным клаузы однобуквенные имена
+// в порядке появления.
+func renameClauseVars(lits []*Literal) []*Literal {
+	names := []rune(clauseVarNames)
+	env := make(map[string]Term)
+	var collect func(t Term)
+	collect = func(t Term) {
+		switch v := t.(type) {
+		case *Variable:
+			if _, ok := env[v.name]; !ok && len(env) < len(names) {
+				env[v.name] = NewVariable(string(names[len(env)]))
+			}
+		case *Function:
+			for _, a := range v.args {
+				collect(a)
+			}
+		}
+	}
+	for _, l := range lits {
+		for _, a := range l.Args {
+			collect(a)
+		}
+	}
+
+	result := make([]*Literal, len(lits))
+	for i, l := range lits {
+		args := make([]Term, len(l.Args))
+		for j, a := range l.Args {
+			args[j] = substituteTerm(a, env)
+		}
+		result[i] = NewLiteral(l.Predicate, args, l.Negated)
+	}
+	return result
+}
@@ -0,0 +1,262 @@
+package resolution
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ==========================================
+// Дерево доказательства: DOT, Mermaid и SVG
+// ==========================================
+
+// FormatDOT записывает доказательство как граф Graphviz: клаузы — узлы,
+// шаги резолюции — рёбра от родителей к резольвенте с подписью-унификатором.
+func FormatDOT(result ProofResult) string {
+	if !result.Success {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("digraph proof {\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	for _, c := range result.Chain {
+		attrs := fmt.Sprintf("label=%s", dotQuote(c.String()))
+		switch {
+		case c.IsEmpty():
+			attrs += ", shape=doublecircle"
+		case c.IsGoal():
+			attrs += ", style=\"rounded,bold\""
+		}
+		fmt.Fprintf(&b, "  c%d [%s];\n", c.ID, attrs)
+	}
+	for _, c := range result.Chain {
+		for _, parent := range c.Parents {
+			if parent == nil {
+				continue
+			}
+			if label := edgeLabel(parent, c); label != "" {
+				fmt.Fprintf(&b, "  c%d -> c%d [label=%s];\n", parent.ID, c.ID, dotQuote(label))
+			} else {
+				fmt.Fprintf(&b, "  c%d -> c%d;\n", parent.ID, c.ID)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// FormatMermaid записывает доказательство как блок-схему Mermaid.
+func FormatMermaid(result ProofResult) string {
+	if !result.Success {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	var goals []string
+	for _, c := range result.Chain {
+		if c.IsEmpty() {
+			fmt.Fprintf(&b, "  c%d((%s))\n", c.ID, mermaidQuote(c.String()))
+		} else {
+			fmt.Fprintf(&b, "  c%d[%s]\n", c.ID, mermaidQuote(c.String()))
+		}
+		if c.IsGoal() {
+			goals = append(goals, fmt.Sprintf("c%d", c.ID))
+		}
+	}
+	for _, c := range result.Chain {
+		for _, parent := range c.Parents {
+			if parent == nil {
+				continue
+			}
+			if label := edgeLabel(parent, c); label != "" {
+				fmt.Fprintf(&b, "  c%d -->|%s| c%d\n", parent.ID, mermaidQuote(label), c.ID)
+			} else {
+				fmt.Fprintf(&b, "  c%d --> c%d\n", parent.ID, c.ID)
+			}
+		}
+	}
+	if len(goals) > 0 {
+		b.WriteString("  classDef goal stroke-width:3px\n")
+		fmt.Fprintf(&b, "  class %s goal\n", strings.Join(goals, ","))
+	}
+	return b.String()
+}
+
+// edgeLabel — часть унификатора шага, относящаяся к переменным родителя.
+func edgeLabel(parent, child *Clause) string {
+	vars := clauseVariables(parent)
+	restricted := make(Theta)
+	for name, t := range child.Subst {
+		if vars[name] {
+			restricted[name] = t
+		}
+	}
+	return formatTheta(restricted)
+}
+
+func clauseVariables(c *Clause) map[string]bool {
+	vars := make(map[string]bool)
+	var walk func(t Term)
+	walk = func(t Term) {
+		switch v := t.(type) {
+		case *Variable:
+			vars[v.name] = true
+		case *Function:
+			for _, a := range v.args {
+				walk(a)
+			}
+		}
+	}
+	for _, l := range c.Literals {
+		for _, a := range l.Args {
+			walk(a)
+		}
+	}
+	return vars
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// Размеры для SVG-раскладки (в пикселях)
+const (
+	svgCharWidth  = 8
+	svgNodePad    = 12
+	svgNodeHeight = 30
+	svgLayerGap   = 70
+	svgNodeGap    = 24
+	svgMargin     = 20
+)
+
+type svgNode struct {
+	clause *Clause
+	layer  int
+	x, y   float64 // центр узла
+	width  float64
+}
+
+// RenderSVG рисует дерево доказательства послойно: начальные клаузы сверху,
+// каждая резольвента — на слой ниже самого глубокого из родителей,
+// □ — внизу. Внутри слоя узлы упорядочены по средней позиции родителей,
+// чтобы уменьшить число пересечений рёбер.
+func RenderSVG(result ProofResult) string {
+	if !result.Success || len(result.Chain) == 0 {
+		return ""
+	}
+
+	nodes := make(map[int]*svgNode, len(result.Chain))
+	var layers [][]*svgNode
+	for _, c := range result.Chain {
+		n := &svgNode{clause: c}
+		for _, parent := range c.Parents {
+			if p, ok := nodes[parentID(parent)]; ok && p.layer+1 > n.layer {
+				n.layer = p.layer + 1
+			}
+		}
+		n.width = float64(utf8.RuneCountInString(c.String())*svgCharWidth + 2*svgNodePad)
+		nodes[c.ID] = n
+		for len(layers) <= n.layer {
+			layers = append(layers, nil)
+		}
+		layers[n.layer] = append(layers[n.layer], n)
+	}
+
+	// Раскладка слоёв: сначала слева направо, затем центрирование по ширине
+	var totalWidth float64
+	for i, layer := range layers {
+		if i > 0 {
+			sort.SliceStable(layer, func(a, b int) bool {
+				return parentsCenter(layer[a], nodes) < parentsCenter(layer[b], nodes)
+			})
+		}
+		x := float64(svgMargin)
+		for _, n := range layer {
+			n.x = x + n.width/2
+			n.y = float64(svgMargin + i*(svgNodeHeight+svgLayerGap) + svgNodeHeight/2)
+			x += n.width + svgNodeGap
+		}
+		if w := x - svgNodeGap + svgMargin; w > totalWidth {
+			totalWidth = w
+		}
+	}
+	for _, layer := range layers {
+		last := layer[len(layer)-1]
+		shift := (totalWidth - (last.x + last.width/2 + svgMargin)) / 2
+		for _, n := range layer {
+			n.x += shift
+		}
+	}
+	totalHeight := svgMargin*2 + len(layers)*svgNodeHeight + (len(layers)-1)*svgLayerGap
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="proof-tree" width="%.0f" height="%d" viewBox="0 0 %.0f %d">`+"\n",
+		totalWidth, totalHeight, totalWidth, totalHeight)
+
+	// Рёбра рисуются до узлов, чтобы узлы их перекрывали
+	for _, c := range result.Chain {
+		child := nodes[c.ID]
+		for _, parent := range c.Parents {
+			p, ok := nodes[parentID(parent)]
+			if !ok {
+				continue
+			}
+			x1, y1 := p.x, p.y+svgNodeHeight/2
+			x2, y2 := child.x, child.y-svgNodeHeight/2
+			fmt.Fprintf(&b, `  <line class="edge" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`+"\n", x1, y1, x2, y2)
+			if label := edgeLabel(parent, c); label != "" {
+				fmt.Fprintf(&b, `  <text class="unifier" x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n",
+					(x1+x2)/2, (y1+y2)/2, html.EscapeString(label))
+			}
+		}
+	}
+
+	for _, c := range result.Chain {
+		n := nodes[c.ID]
+		class := "clause"
+		switch {
+		case c.IsEmpty():
+			class += " empty"
+		case c.IsGoal():
+			class += " goal"
+		case c.Origin == "init":
+			class += " premise"
+		}
+		fmt.Fprintf(&b, `  <g class="%s"><rect x="%.1f" y="%.1f" width="%.0f" height="%d" rx="8"/>`,
+			class, n.x-n.width/2, n.y-svgNodeHeight/2, n.width, svgNodeHeight)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text></g>`+"\n",
+			n.x, n.y, html.EscapeString(c.String()))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func parentID(c *Clause) int {
+	if c == nil {
+		return 0
+	}
+	return c.ID
+}
+
+// parentsCenter — средняя горизонтальная позиция родителей узла.
+func parentsCenter(n *svgNode, nodes map[int]*svgNode) float64 {
+	var sum float64
+	var count int
+	for _, parent := range n.clause.Parents {
+		if p, ok := nodes[parentID(parent)]; ok {
+			sum += p.x
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,62 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProofGraphs(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	dot := FormatDOT(res)
+	for _, want := range []string{
+		"digraph proof {",
+		`c2 [label="¬Человек(x) ∨ Смертен(x)"];`,
+		`c3 [label="¬Смертен(Сократ)", style="rounded,bold"];`,
+		`c2 -> c4 [label="Сократ/x"];`,
+		"c3 -> c4;",
+		`c5 [label="□", shape=doublecircle];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT does not contain %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := FormatMermaid(res)
+	for _, want := range []string{
+		"graph TD",
+		`c5(("□"))`,
+		`c2 -->|"Сократ/x"| c4`,
+		"class c3 goal",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid does not contain %q:\n%s", want, mermaid)
+		}
+	}
+
+	svg := RenderSVG(res)
+	if !strings.HasPrefix(svg, "<svg ") || strings.Count(svg, "<rect ") != len(res.Chain) {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+	if strings.Count(svg, `<line class="edge"`) != 4 {
+		t.Errorf("expected 4 edges in SVG:\n%s", svg)
+	}
+}
+
+func TestProofGraphsFailure(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A)", "¬Q(A)"})
+	res := engine.Prove()
+	if FormatDOT(res) != "" || FormatMermaid(res) != "" || RenderSVG(res) != "" {
+		t.Fatal("expected empty graphs for a failed proof")
+	}
+}
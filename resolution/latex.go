@@ -0,0 +1,126 @@
+package resolution
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ==========================================
+// Вывод доказательства в LaTeX
+// ==========================================
+
+// FormatLaTeX записывает доказательство для конспектов: нумерованную таблицу
+// клауз с обоснованием каждой и дерево вывода в стиле bussproofs.
+// Нужны пакеты amsmath, amssymb и bussproofs. Общие подвыводы в дереве
+// повторяются, поскольку bussproofs рисует только деревья.
+func FormatLaTeX(result ProofResult) string {
+	if !result.Success {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("% \\usepackage{amsmath,amssymb,bussproofs}\n")
+
+	b.WriteString("\\begin{tabular}{rll}\n")
+	b.WriteString("\\textnumero & Клауза & Обоснование \\\\\n\\hline\n")
+	for _, c := range result.Chain {
+		var reason string
+		switch {
+		case c.Origin == "res":
+			reason = fmt.Sprintf("резолюция (%d), (%d)", c.Parents[0].ID, c.Parents[1].ID)
+			if len(c.Subst) > 0 {
+				reason += ", $" + latexSubst(c.Subst) + "$"
+			}
+		case c.IsGoal():
+			reason = "отрицание цели"
+		default:
+			reason = "посылка"
+		}
+		fmt.Fprintf(&b, "(%d) & $%s$ & %s \\\\\n", c.ID, latexClause(c), reason)
+	}
+	b.WriteString("\\end{tabular}\n\n")
+
+	b.WriteString("\\begin{prooftree}\n")
+	var tree func(c *Clause)
+	tree = func(c *Clause) {
+		if c.Origin != "res" {
+			fmt.Fprintf(&b, "\\AxiomC{$%s$}\n", latexClause(c))
+			return
+		}
+		tree(c.Parents[0])
+		tree(c.Parents[1])
+		if len(c.Subst) > 0 {
+			fmt.Fprintf(&b, "\\RightLabel{\\scriptsize $%s$}\n", latexSubst(c.Subst))
+		}
+		fmt.Fprintf(&b, "\\BinaryInfC{$%s$}\n", latexClause(c))
+	}
+	tree(result.Chain[len(result.Chain)-1])
+	b.WriteString("\\end{prooftree}\n")
+	return b.String()
+}
+
+func latexClause(c *Clause) string {
+	if c.IsEmpty() {
+		return "\\Box"
+	}
+	parts := make([]string, len(c.Literals))
+	for i, l := range c.Literals {
+		parts[i] = latexLiteral(l)
+	}
+	return strings.Join(parts, " \\lor ")
+}
+
+func latexLiteral(l *Literal) string {
+	s := latexName(l.Predicate)
+	if len(l.Args) > 0 {
+		args := make([]string, len(l.Args))
+		for i, a := range l.Args {
+			args[i] = latexTerm(a)
+		}
+		s += "(" + strings.Join(args, ", ") + ")"
+	}
+	if l.Negated {
+		s = "\\neg " + s
+	}
+	return s
+}
+
+func latexTerm(t Term) string {
+	switch v := t.(type) {
+	case *Variable:
+		return latexEscape(v.name)
+	case *Function:
+		args := make([]string, len(v.args))
+		for i, a := range v.args {
+			args[i] = latexTerm(a)
+		}
+		return latexName(v.name) + "(" + strings.Join(args, ", ") + ")"
+	}
+	return latexName(t.String())
+}
+
+// latexSubst записывает подстановку в виде \{t/x, ...\}.
+func latexSubst(subst Theta) string {
+	parts := make([]string, 0, len(subst))
+	for name, t := range subst {
+		parts = append(parts, latexTerm(t)+"/"+latexEscape(name))
+	}
+	sort.Strings(parts)
+	return "\\{" + strings.Join(parts, ", ") + "\\}"
+}
+
+// latexName — имя предиката, функции или константы прямым шрифтом.
+func latexName(name string) string {
+	return "\\text{" + latexEscape(name) + "}"
+}
+
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`, `}`, `\}`,
+	`_`, `\_`, `^`, `\^{}`,
+	`#`, `\#`, `&`, `\&`,
+	`%`, `\%`, `$`, `\$`,
+	`~`, `\~{}`,
+)
+
+func latexEscape(s string) string { return latexReplacer.Replace(s) }
@@ -0,0 +1,42 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatLaTeX(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	out := FormatLaTeX(res)
+	for _, want := range []string{
+		`(2) & $\neg \text{Человек}(x) \lor \text{Смертен}(x)$ & посылка \\`,
+		`(3) & $\neg \text{Смертен}(\text{Сократ})$ & отрицание цели \\`,
+		`(4) & $\neg \text{Человек}(\text{Сократ})$ & резолюция (2), (3), $\{\text{Сократ}/x\}$ \\`,
+		"\\begin{prooftree}\n\\AxiomC{$\\text{Человек}(\\text{Сократ})$}\n",
+		`\RightLabel{\scriptsize $\{\text{Сократ}/x\}$}`,
+		"\\BinaryInfC{$\\Box$}\n\\end{prooftree}\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, `\AxiomC`) != 3 || strings.Count(out, `\BinaryInfC`) != 2 {
+		t.Errorf("unexpected tree shape:\n%s", out)
+	}
+}
+
+func TestLaTeXEscape(t *testing.T) {
+	if got := latexName("Ск_1"); got != `\text{Ск\_1}` {
+		t.Errorf("unexpected escaping: %s", got)
+	}
+}
@@ -0,0 +1,527 @@
+package resolution
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ==========================================
+// Ручной ввод: клаузы и формулы логики первого порядка
+// ==========================================
+//
+// Каждая непустая строка — отдельное утверждение:
+//   ¬Человек(x) ∨ Смертен(x)          — клауза в синтаксисе движка;
+//   ∀x (Человек(x) → Смертен(x))      — формула (∀ ∃ ¬ ∧ ∨ → ↔ ⊤ ⊥, скобки);
+//   ⊢ Смертен(Сократ)                 — цель: её отрицание становится RoleNegatedConjecture.
+// Цель обязательна: без строки «⊢» ввод отклоняется с ошибкой ErrNoGoal.
+// Строки, начинающиеся с '%' или '#', — комментарии. Переменные — одна
+// строчная буква или имя, связанное квантором; имена предикатов, функций
+// и констант начинаются с заглавной буквы.
+
+// LineError — ошибка разбора одной строки ручного ввода.
+type LineError struct {
+	Line int // номер строки, с 1
+	Msg  string
+}
+
+func (e LineError) Error() string { return fmt.Sprintf("строка %d: %s", e.Line, e.Msg) }
+
+// ParseErrors — ошибки всех строк ручного ввода.
+type ParseErrors []LineError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, le := range e {
+		msgs[i] = le.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ParseManual заменяет базу знаний утверждениями из text. Если хотя бы одна
+// строка не разобрана, база знаний не меняется, а ошибка имеет тип
+// ParseErrors со списком всех неверных строк. Непустой ввод без цели
+// отклоняется с ошибкой ErrNoGoal.
+func (e *ResolutionEngine) ParseManual(text string) ([]*Clause, error) {
+	type statement struct {
+		line    int
+		source  string
+		goal    bool
+		clause  []*Literal // строка в синтаксисе клауз
+		formula *formula   // строка-формула
+	}
+
+	var statements []statement
+	var errs ParseErrors
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		st := statement{line: i + 1, source: line}
+		body := line
+		if strings.HasPrefix(body, "⊢") {
+			st.goal = true
+			body = strings.TrimSpace(strings.TrimPrefix(body, "⊢"))
+		}
+
+		p, err := newFormulaParser(body)
+		if err == nil {
+			if !st.goal && p.isClauseSyntax() {
+				st.clause, err = p.parseClause()
+			} else {
+				st.formula, err = p.parseFormula()
+			}
+		}
+		if err != nil {
+			errs = append(errs, LineError{Line: i + 1, Msg: err.Error()})
+			continue
+		}
+		statements = append(statements, st)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	hasGoal := false
+	for _, st := range statements {
+		hasGoal = hasGoal || st.goal
+	}
+	if len(statements) > 0 && !hasGoal {
+		return nil, ErrNoGoal
+	}
+
+	cl := newClausifier()
+	for _, st := range statements {
+		if st.formula != nil {
+			cl.reserve(st.formula)
+		}
+		for _, l := range st.clause {
+			cl.reserve(atomFormula(l.Predicate, l.Args))
+		}
+	}
+
+	// Движок не переименовывает переменные при резолюции, поэтому клаузы
+	// из формул получают переменные, не встречающиеся в других клаузах
+	used := make(map[string]bool)
+	for _, st := range statements {
+		for name := range clauseVariables(&Clause{Literals: st.clause}) {
+			used[name] = true
+		}
+	}
+
+	e.clauses = make([]*Clause, 0)
+	e.clauseCounter = 1
+	e.background = nil
+	for _, st := range statements {
+		role := RoleAxiom
+		var clauses [][]*Literal
+		switch {
+		case st.clause != nil:
+			clauses = [][]*Literal{st.clause}
+		case st.goal:
+			// Свободные переменные цели связываются до отрицания: ⊢ P(x) — это ⊢ ∀x P(x)
+			role = RoleNegatedConjecture
+			clauses = cl.clausify(notFormula(universalClosure(st.formula)))
+		default:
+			clauses = cl.clausify(st.formula)
+		}
+		for _, lits := range clauses {
+			if st.clause == nil {
+				lits = renameApart(lits, used)
+			}
+			c := NewClause(e.getNextID(), lits, "init", [2]*Clause{}, "")
+			c.Role = role
+			c.Source = st.source
+			e.clauses = append(e.clauses, c)
+		}
+	}
+	return e.Clauses(), nil
+}
+
+// renameApart переименовывает переменные клаузы в ещё не занятые (used)
+// и отмечает новые имена занятыми. Когда свободные имена заканчиваются,
+// они начинают повторяться.
+func renameApart(lits []*Literal, used map[string]bool) []*Literal {
+	names := []rune(clauseVarNames)
+	env := make(map[string]Term)
+	var collect func(t Term)
+	collect = func(t Term) {
+		switch v := t.(type) {
+		case *Variable:
+			if _, ok := env[v.name]; ok {
+				return
+			}
+			if len(used) >= len(names) {
+				clear(used)
+			}
+			for _, r := range names {
+				if name := string(r); !used[name] {
+					used[name] = true
+					env[v.name] = NewVariable(name)
+					break
+				}
+			}
+		case *Function:
+			for _, a := range v.args {
+				collect(a)
+			}
+		}
+	}
+	for _, l := range lits {
+		for _, a := range l.Args {
+			collect(a)
+		}
+	}
+
+	result := make([]*Literal, len(lits))
+	for i, l := range lits {
+		args := make([]Term, len(l.Args))
+		for j, a := range l.Args {
+			args[j] = substituteTerm(a, env)
+		}
+		result[i] = NewLiteral(l.Predicate, args, l.Negated)
+	}
+	return result
+}
+
+// ==========================================
+// Лексер и парсер формул
+// ==========================================
+
+type formulaToken struct {
+	kind string // "name", "sym" или "eof"
+	text string
+	pos  int // позиция в строке (в символах, с 1)
+}
+
+const formulaSymbols = "¬∧∨→↔∀∃⊤⊥(),.:"
+
+func tokenizeFormula(s string) ([]formulaToken, error) {
+	var toks []formulaToken
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune(formulaSymbols, r):
+			toks = append(toks, formulaToken{kind: "sym", text: string(r), pos: i + 1})
+			i++
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			toks = append(toks, formulaToken{kind: "name", text: string(runes[start:i]), pos: start + 1})
+		default:
+			return nil, fmt.Errorf("позиция %d: недопустимый символ %q", i+1, r)
+		}
+	}
+	toks = append(toks, formulaToken{kind: "eof", pos: len(runes) + 1})
+	return toks, nil
+}
+
+type formulaParser struct {
+	toks  []formulaToken
+	pos   int
+	bound []string // переменные, связанные кванторами в текущей области
+}
+
+func newFormulaParser(s string) (*formulaParser, error) {
+	toks, err := tokenizeFormula(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 1 {
+		return nil, fmt.Errorf("пустая формула")
+	}
+	return &formulaParser{toks: toks}, nil
+}
+
+func (p *formulaParser) peek() formulaToken { return p.toks[p.pos] }
+
+func (p *formulaParser) next() formulaToken {
+	t := p.toks[p.pos]
+	if t.kind != "eof" {
+		p.pos++
+	}
+	return t
+}
+
+func (p *formulaParser) atSym(sym string) bool {
+	t := p.peek()
+	return t.kind == "sym" && t.text == sym
+}
+
+func (p *formulaParser) expect(sym string) error {
+	if !p.atSym(sym) {
+		return p.errorf("ожидалось %q", sym)
+	}
+	p.next()
+	return nil
+}
+
+func (p *formulaParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	found := "конец строки"
+	if t.kind != "eof" {
+		found = fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Errorf("позиция %d: %s, найдено %s", t.pos, fmt.Sprintf(format, args...), found)
+}
+
+// isClauseSyntax сообщает, что в строке нет связок, кроме ¬ и ∨.
+func (p *formulaParser) isClauseSyntax() bool {
+	for _, t := range p.toks {
+		if t.kind == "sym" && strings.Contains("∧→↔∀∃⊤⊥.:", t.text) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseClause разбирает строку вида L1 ∨ L2 ∨ ..., где L — атом или ¬атом.
+func (p *formulaParser) parseClause() ([]*Literal, error) {
+	var lits []*Literal
+	for {
+		negated := false
+		if p.atSym("¬") {
+			p.next()
+			negated = true
+		}
+		pred, args, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		lits = append(lits, NewLiteral(pred, args, negated))
+		if !p.atSym("∨") {
+			break
+		}
+		p.next()
+	}
+	if p.peek().kind != "eof" {
+		return nil, p.errorf("ожидалось '∨' или конец клаузы")
+	}
+	return lits, nil
+}
+
+func (p *formulaParser) parseFormula() (*formula, error) {
+	f, err := p.parseIff()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != "eof" {
+		return nil, p.errorf("лишний текст после формулы")
+	}
+	return f, nil
+}
+
+func (p *formulaParser) parseIff() (*formula, error) {
+	f, err := p.parseImplies()
+	if err != nil {
+		return nil, err
+	}
+	for p.atSym("↔") {
+		p.next()
+		r, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		f = binaryFormula(opIff, f, r)
+	}
+	return f, nil
+}
+
+// parseImplies: импликация правоассоциативна (A → B → C = A → (B → C)).
+func (p *formulaParser) parseImplies() (*formula, error) {
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.atSym("→") {
+		p.next()
+		r, err := p.parseImplies()
+		if err != nil {
+			return nil, err
+		}
+		f = binaryFormula(opImplies, f, r)
+	}
+	return f, nil
+}
+
+func (p *formulaParser) parseOr() (*formula, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.atSym("∨") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		f = binaryFormula(opOr, f, r)
+	}
+	return f, nil
+}
+
+func (p *formulaParser) parseAnd() (*formula, error) {
+	f, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.atSym("∧") {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		f = binaryFormula(opAnd, f, r)
+	}
+	return f, nil
+}
+
+// parseUnary: ¬F, кванторы, скобки, ⊤, ⊥ и атомы. Тело квантора
+// простирается как можно дальше вправо: ∀x P(x) → Q(x) = ∀x (P(x) → Q(x)).
+func (p *formulaParser) parseUnary() (*formula, error) {
+	switch {
+	case p.atSym("¬"):
+		p.next()
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notFormula(f), nil
+	case p.atSym("∀"), p.atSym("∃"):
+		op := opForall
+		if p.next().text == "∃" {
+			op = opExists
+		}
+		var vars []string
+		for {
+			t := p.peek()
+			if t.kind != "name" || !isVariableName(t.text) {
+				return nil, p.errorf("ожидалась переменная (имя со строчной буквы)")
+			}
+			p.next()
+			vars = append(vars, t.text)
+			if !p.atSym(",") {
+				break
+			}
+			p.next()
+		}
+		if p.atSym(".") || p.atSym(":") {
+			p.next()
+		}
+
+		saved := p.bound
+		p.bound = append(p.bound[:len(p.bound):len(p.bound)], vars...)
+		body, err := p.parseIff()
+		p.bound = saved
+		if err != nil {
+			return nil, err
+		}
+		return quantFormula(op, vars, body), nil
+	case p.atSym("("):
+		p.next()
+		f, err := p.parseIff()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case p.atSym("⊤"):
+		p.next()
+		return &formula{op: opTrue}, nil
+	case p.atSym("⊥"):
+		p.next()
+		return &formula{op: opFalse}, nil
+	}
+	pred, args, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	return atomFormula(pred, args), nil
+}
+
+func (p *formulaParser) parseAtom() (string, []Term, error) {
+	t := p.peek()
+	if t.kind != "name" {
+		return "", nil, p.errorf("ожидался предикат")
+	}
+	if !isSymbolName(t.text) {
+		return "", nil, p.errorf("имя предиката должно начинаться с заглавной буквы")
+	}
+	p.next()
+	args, err := p.parseArgList()
+	return t.text, args, err
+}
+
+// parseArgList разбирает необязательный список аргументов в скобках.
+func (p *formulaParser) parseArgList() ([]Term, error) {
+	if !p.atSym("(") {
+		return nil, nil
+	}
+	p.next()
+	var args []Term
+	for {
+		a, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+		if !p.atSym(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (p *formulaParser) parseTerm() (Term, error) {
+	t := p.peek()
+	if t.kind != "name" {
+		return nil, p.errorf("ожидался терм")
+	}
+	p.next()
+	if isSymbolName(t.text) {
+		args, err := p.parseArgList()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 0 {
+			return NewConstant(t.text), nil
+		}
+		return NewFunction(t.text, args), nil
+	}
+	if p.isBound(t.text) || isSingleLowerLetter(t.text) {
+		return NewVariable(t.text), nil
+	}
+	p.pos--
+	return nil, p.errorf("переменная должна быть одной строчной буквой или связана квантором")
+}
+
+func (p *formulaParser) isBound(name string) bool {
+	for _, v := range p.bound {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isSymbolName: имена предикатов, функций и констант — с заглавной буквы.
+func isSymbolName(s string) bool {
+	r := []rune(s)
+	return unicode.IsUpper(r[0])
+}
+
+func isVariableName(s string) bool {
+	r := []rune(s)
+	return unicode.IsLower(r[0])
+}
@@ -0,0 +1,94 @@
+package resolution
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseManual(t *testing.T) {
+	engine := NewResolutionEngine()
+	clauses, err := engine.ParseManual(`
+% Силлогизм
+∀x (Человек(x) → Смертен(x))
+Человек(Сократ)
+⊢ Смертен(Сократ)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range clauses {
+		got = append(got, string(c.Role)+": "+c.String())
+	}
+	want := []string{
+		"axiom: ¬Человек(x) ∨ Смертен(x)",
+		"axiom: Человек(Сократ)",
+		"negated_conjecture: ¬Смертен(Сократ)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected clauses:\n%s", strings.Join(got, "\n"))
+	}
+	if clauses[0].Source != "∀x (Человек(x) → Смертен(x))" {
+		t.Errorf("unexpected source %q", clauses[0].Source)
+	}
+
+	if res := engine.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestParseManualExistentialGoal(t *testing.T) {
+	engine := NewResolutionEngine()
+	clauses, err := engine.ParseManual("∀x∃y Любит(x, y)\n⊢ ∃y Любит(Иван, y)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clauses[0].String() != "Любит(x, Ск1(x))" || clauses[1].String() != "¬Любит(Иван, y)" {
+		t.Fatalf("unexpected clauses: %v, %v", clauses[0], clauses[1])
+	}
+	if res := engine.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestParseManualRequiresGoal(t *testing.T) {
+	// Опровержение на одних клаузах без «⊢» — не доказательство цели
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A)"})
+
+	_, err := engine.ParseManual("¬Человек(x) ∨ Смертен(x)\nЧеловек(Сократ)\n¬Смертен(Сократ)")
+	if !errors.Is(err, ErrNoGoal) {
+		t.Fatalf("expected ErrNoGoal, got %v", err)
+	}
+	if clauses := engine.Clauses(); len(clauses) != 1 || clauses[0].String() != "P(A)" {
+		t.Errorf("knowledge base changed: %v", clauses)
+	}
+}
+
+func TestParseManualErrors(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A)"})
+
+	_, err := engine.ParseManual("Человек(Сократ\nЧеловек(Платон)\n¬человек(x)\n∀x P(x) ∧\nЛюбит(кто, Мария)")
+	var errs ParseErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ParseErrors, got %v", err)
+	}
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Line)
+	}
+	if len(lines) != 4 || lines[0] != 1 || lines[1] != 3 || lines[2] != 4 || lines[3] != 5 {
+		t.Fatalf("unexpected error lines %v:\n%v", lines, err)
+	}
+	if !strings.Contains(errs[0].Error(), "строка 1: позиция 15: ожидалось \")\"") {
+		t.Errorf("unexpected message: %v", errs[0])
+	}
+
+	// При ошибках база знаний не меняется
+	if clauses := engine.Clauses(); len(clauses) != 1 || clauses[0].String() != "P(A)" {
+		t.Errorf("knowledge base changed: %v", clauses)
+	}
+}
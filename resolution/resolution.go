@@ -9,6 +9,18 @@ import (
 
 const max_iterations = 500000
 
+// Лимит для проверки непротиворечивости посылок: насыщение базы без цели
+// может не завершаться (функции Сколема), поэтому проверка заведомо короче.
+const consistency_iterations = 5000
+
+// Лимит для поиска более короткого доказательства: это необязательная
+// доработка уже найденного вывода, она не должна заметно замедлять ответ.
+const shorten_iterations = 20000
+
+// Лимит одной проверки при минимизации ядра: кандидатов столько же, сколько
+// посылок, и каждый из них с полным лимитом умножал бы время ответа.
+const core_iterations = 5000
+
 // ==========================================
 // 1. Базовые структуры (Термы)
 // ==========================================
@@ -122,14 +134,42 @@ func (l *Literal) Equal(other *Literal) bool {
 	return true
 }
 
+// Role — роль начальной клаузы в задаче (названия совпадают с ролями TPTP).
+type Role string
+
+const (
+	RoleAxiom             Role = "axiom"              // общее правило или знание
+	RoleHypothesis        Role = "hypothesis"         // условие конкретной задачи
+	RoleNegatedConjecture Role = "negated_conjecture" // отрицание доказываемой цели
+)
+
+// ParseRole проверяет строковое название роли. Роль обязательна: пропущенная
+// роль цели иначе превратила бы её в аксиому.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(strings.TrimSpace(s)); r {
+	case "":
+		return "", fmt.Errorf("не указана роль клаузы (%s, %s или %s)", RoleAxiom, RoleHypothesis, RoleNegatedConjecture)
+	case RoleAxiom, RoleHypothesis, RoleNegatedConjecture:
+		return r, nil
+	default:
+		return "", fmt.Errorf("неизвестная роль клаузы: %q", s)
+	}
+}
+
 type Clause struct {
 	ID       int
 	Literals []*Literal
 	Origin   string
 	Parents  [2]*Clause
 	Rule     string
+	Source   string // исходное предложение на естественном языке (для init-клауз)
+	Role     Role   // роль в задаче (для init-клауз)
+	Subst    Theta  // унификатор шага резолюции (для res-клауз), значения полностью применены
 }
 
+// IsGoal сообщает, получена ли начальная клауза из отрицания цели.
+func (c *Clause) IsGoal() bool { return c.Role == RoleNegatedConjecture }
+
 func NewClause(id int, literals []*Literal, origin string, parents [2]*Clause, rule string) *Clause {
 	uniqueLiterals := removeDuplicateLiterals(literals)
 	// Сортировка для детерминизма
@@ -290,6 +330,20 @@ func unifyVar(varTerm Term, x Term, theta Theta) (Theta, bool) {
 type ResolutionEngine struct {
 	clauses       []*Clause
 	clauseCounter int
+
+	// MaxIterations — лимит проверок пар клауз при поиске доказательства
+	// (0 — значение по умолчанию max_iterations)
+	MaxIterations int
+
+	// background — насыщенная база знаний для Query; nil — не вычислена
+	// или база знаний изменилась
+	background *saturation
+}
+
+// saturation — база знаний вместе со всеми её резольвентами.
+type saturation struct {
+	clauses []*Clause
+	next    int // первый номер клауз, не занятый резольвентами
 }
 
 func NewResolutionEngine() *ResolutionEngine {
@@ -299,16 +353,117 @@ func NewResolutionEngine() *ResolutionEngine {
 	}
 }
 
+func (e *ResolutionEngine) iterationLimit() int {
+	if e.MaxIterations > 0 {
+		return e.MaxIterations
+	}
+	return max_iterations
+}
+
 func (e *ResolutionEngine) getNextID() int {
 	id := e.clauseCounter
 	e.clauseCounter++
 	return id
 }
 
+// ParseInput заменяет базу знаний движка клаузами из inputStrings.
 func (e *ResolutionEngine) ParseInput(inputStrings []string) {
 	e.clauses = make([]*Clause, 0)
 	e.clauseCounter = 1
+	e.background = nil
+	e.AddClauses(inputStrings)
+}
+
+// AddClauses добавляет клаузы к базе знаний, не сбрасывая уже разобранные.
+// Возвращает созданные клаузы (с присвоенными ID).
+func (e *ResolutionEngine) AddClauses(inputStrings []string) []*Clause {
+	added := e.parseClauses(inputStrings)
+	e.clauses = append(e.clauses, added...)
+	e.background = nil
+	return added
+}
 
+// InputClause — клауза в текстовом виде вместе с предложением задачи,
+// из которого она получена, и ролью. Роль обязательна (см. ParseRole):
+// AddInput переносит её в клаузу как есть.
+type InputClause struct {
+	Text   string
+	Source string
+	Role   Role
+}
+
+// AddInput добавляет клаузы с привязкой к исходным предложениям.
+func (e *ResolutionEngine) AddInput(inputs []InputClause) []*Clause {
+	added := e.newInputClauses(inputs)
+	e.clauses = append(e.clauses, added...)
+	e.background = nil
+	return added
+}
+
+func (e *ResolutionEngine) newInputClauses(inputs []InputClause) []*Clause {
+	texts := make([]string, len(inputs))
+	for i, in := range inputs {
+		texts[i] = in.Text
+	}
+	result := e.parseClauses(texts)
+	for i, c := range result {
+		c.Source = inputs[i].Source
+		c.Role = inputs[i].Role
+	}
+	return result
+}
+
+// splitGoal разделяет базу знаний на посылки и клаузы отрицания цели.
+func (e *ResolutionEngine) splitGoal() (premises, goal []*Clause) {
+	for _, c := range e.clauses {
+		if c.IsGoal() {
+			goal = append(goal, c)
+		} else {
+			premises = append(premises, c)
+		}
+	}
+	return premises, goal
+}
+
+// Retract удаляет из базы знаний клаузы с указанными ID.
+func (e *ResolutionEngine) Retract(ids ...int) error {
+	toRemove := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		toRemove[id] = true
+	}
+
+	kept := make([]*Clause, 0, len(e.clauses))
+	for _, c := range e.clauses {
+		if toRemove[c.ID] {
+			delete(toRemove, c.ID)
+			continue
+		}
+		kept = append(kept, c)
+	}
+
+	if len(toRemove) > 0 {
+		missing := make([]int, 0, len(toRemove))
+		for id := range toRemove {
+			missing = append(missing, id)
+		}
+		sort.Ints(missing)
+		return fmt.Errorf("клаузы не найдены в базе знаний: %v", missing)
+	}
+
+	e.clauses = kept
+	e.background = nil
+	return nil
+}
+
+// Clauses возвращает текущую базу знаний движка.
+func (e *ResolutionEngine) Clauses() []*Clause {
+	result := make([]*Clause, len(e.clauses))
+	copy(result, e.clauses)
+	return result
+}
+
+func (e *ResolutionEngine) parseClauses(inputStrings []string) []*Clause {
+	result := make([]*Clause, 0, len(inputStrings))
 	for _, s := range inputStrings {
 		// Разделяем по ИЛИ
 		literalsStr := strings.Split(s, "∨")
@@ -331,9 +486,19 @@ func (e *ResolutionEngine) ParseInput(inputStrings []string) {
 			}
 		}
 
-		newClause := NewClause(e.getNextID(), literals, "init", [2]*Clause{}, "")
-		e.clauses = append(e.clauses, newClause)
+		clause := NewClause(e.getNextID(), literals, "init", [2]*Clause{}, "")
+		clause.Role = RoleAxiom
+		result = append(result, clause)
 	}
+	return result
+}
+
+// asGoal помечает клаузы как отрицание цели.
+func asGoal(clauses []*Clause) []*Clause {
+	for _, c := range clauses {
+		c.Role = RoleNegatedConjecture
+	}
+	return clauses
 }
 
 // parseLiteralString выделяет P и (args...)
@@ -504,6 +669,10 @@ func (e *ResolutionEngine) resolvePair(c1, c2 *Clause) []*Clause {
 						[2]*Clause{c1, c2},
 						fmt.Sprintf("Унификация %s", unifStr),
 					)
+					resolvent.Subst = make(Theta, len(theta))
+					for k := range theta {
+						resolvent.Subst[k] = e.applyThetaToTerm(NewVariable(k), theta)
+					}
 					resolvents = append(resolvents, resolvent)
 				}
 			}
@@ -532,6 +701,27 @@ type ProofResult struct {
 	Success  bool
 	FullLog  string
 	ShortLog string
+	Chain    []*Clause // цепочка доказательства (только при Success)
+
+	// Clauses — все клаузы на момент остановки поиска (только без Success):
+	// насыщенное множество или, при LimitReached, полученное до лимита
+	Clauses []*Clause
+
+	LimitReached bool // поиск остановлен по лимиту итераций
+
+	Checks    int // число проверенных пар клауз
+	Generated int // число новых (не повторяющихся) резольвент
+}
+
+// Premises возвращает начальные клаузы, использованные в доказательстве.
+func (r ProofResult) Premises() []*Clause {
+	var premises []*Clause
+	for _, c := range r.Chain {
+		if c.Origin == "init" {
+			premises = append(premises, c)
+		}
+	}
+	return premises
 }
 
 func (e *ResolutionEngine) buildProofChain(contradiction *Clause) []*Clause {
@@ -560,7 +750,11 @@ func (e *ResolutionEngine) formatShortLog(chain []*Clause) string {
 	lines = append(lines, "Используемые начальные клаузы:")
 	for _, c := range chain {
 		if c.Origin == "init" {
-			lines = append(lines, fmt.Sprintf("  [%d] %s", c.ID, c.String()))
+			if c.IsGoal() {
+				lines = append(lines, fmt.Sprintf("  [%d] %s (отрицание цели)", c.ID, c.String()))
+			} else {
+				lines = append(lines, fmt.Sprintf("  [%d] %s", c.ID, c.String()))
+			}
 		}
 	}
 	lines = append(lines, "\nШаги резолюции:")
@@ -586,16 +780,81 @@ func (e *ResolutionEngine) formatShortLog(chain []*Clause) string {
 	return strings.Join(lines, "\n")
 }
 
+// Prove ищет опровержение на всей базе знаний. Если в базе есть клаузы
+// с ролью RoleNegatedConjecture, они становятся опорным множеством
+// (как в Query), иначе перебираются все пары клауз.
 func (e *ResolutionEngine) Prove() ProofResult {
-	activeClauses := make([]*Clause, len(e.clauses))
-	copy(activeClauses, e.clauses)
+	premises, goal := e.splitGoal()
+	return e.shorten(e.search(premises, goal, e.iterationLimit()))
+}
+
+// Query доказывает цель относительно текущей базы знаний, не изменяя её.
+// goal — клаузы отрицания цели (в том же формате, что и для ParseInput).
+// При первом вопросе база знаний насыщается (см. saturate), и насыщение
+// переиспользуется, пока база знаний не изменится. Дальше используется
+// стратегия опорного множества: резольвируются только пары, в которых хотя
+// бы одна клауза происходит из цели, поэтому работа с фоновыми знаниями не
+// повторяется от вопроса к вопросу. Клаузы цели и резольвенты нумеруются
+// отдельно от счётчика движка, так что одинаковые вопросы получают
+// одинаковые номера клауз.
+func (e *ResolutionEngine) Query(goal []string) ProofResult {
+	bg, q := e.queryEngine()
+	return q.shorten(q.search(bg, asGoal(q.parseClauses(goal)), e.iterationLimit()))
+}
+
+// QueryInput — то же, что Query, но с привязкой клауз цели к исходным предложениям.
+func (e *ResolutionEngine) QueryInput(goal []InputClause) ProofResult {
+	bg, q := e.queryEngine()
+	return q.shorten(q.search(bg, asGoal(q.newInputClauses(goal)), e.iterationLimit()))
+}
+
+// queryEngine возвращает насыщенную базу знаний и временный движок для
+// одного вопроса: номера его клауз продолжают номера насыщения, не расходуя
+// счётчик e.
+func (e *ResolutionEngine) queryEngine() ([]*Clause, *ResolutionEngine) {
+	bg := e.saturate()
+	return bg.clauses, &ResolutionEngine{clauseCounter: bg.next, MaxIterations: e.MaxIterations}
+}
+
+// saturate насыщает базу знаний (все пары, лимит consistency_iterations)
+// и кэширует результат в e.background. Если насыщение не завершилось
+// в пределах лимита или вывело □, кэшируется сама база знаний: неполное
+// или противоречивое насыщение не сокращает поиск.
+func (e *ResolutionEngine) saturate() *saturation {
+	if e.background == nil {
+		s := &ResolutionEngine{clauseCounter: e.clauseCounter}
+		res := s.search(e.clauses, nil, consistency_iterations)
+		e.background = &saturation{clauses: e.clauses, next: e.clauseCounter}
+		if !res.Success && !res.LimitReached {
+			e.background = &saturation{clauses: res.Clauses, next: s.clauseCounter}
+		}
+	}
+	return e.background
+}
+
+// search — общий цикл насыщения. Если support пуст, резольвируются все пары
+// (полный перебор), иначе — только пары с участием опорных клауз.
+func (e *ResolutionEngine) search(background, support []*Clause, limit int) ProofResult {
+	activeClauses := make([]*Clause, 0, len(background)+len(support))
+	activeClauses = append(activeClauses, background...)
+	activeClauses = append(activeClauses, support...)
 	processedPairs := make(map[[2]int]bool)
 
+	useSupport := len(support) > 0
+	supported := make(map[int]bool, len(support))
+	for _, c := range support {
+		supported[c.ID] = true
+	}
+
 	var logLines []string
 	logLines = append(logLines, "=== ПОЛНЫЙ ЛОГ (все резолюции) ===\n")
 	logLines = append(logLines, fmt.Sprintf("Начальные клаузы: %d", len(activeClauses)))
 	for _, c := range activeClauses {
-		logLines = append(logLines, fmt.Sprintf("  [%d] %s", c.ID, c.String()))
+		if supported[c.ID] {
+			logLines = append(logLines, fmt.Sprintf("  [%d] %s (цель)", c.ID, c.String()))
+		} else {
+			logLines = append(logLines, fmt.Sprintf("  [%d] %s", c.ID, c.String()))
+		}
 	}
 
 	stepCount := 1
@@ -608,13 +867,18 @@ func (e *ResolutionEngine) Prove() ProofResult {
 
 		for i := 0; i < len(currentPool); i++ {
 			for j := i + 1; j < len(currentPool); j++ {
+				c1 := currentPool[i]
+				c2 := currentPool[j]
+				if useSupport && !supported[c1.ID] && !supported[c2.ID] {
+					continue
+				}
+
 				processedChecks++
-				if processedChecks > max_iterations {
-					return ProofResult{Success: false, FullLog: strings.Join(logLines, "\n"), ShortLog: "TIMEOUT"}
+				if processedChecks > limit {
+					return ProofResult{Success: false, FullLog: strings.Join(logLines, "\n"), ShortLog: "TIMEOUT", LimitReached: true,
+						Clauses: activeClauses, Checks: limit, Generated: stepCount - 1}
 				}
 
-				c1 := currentPool[i]
-				c2 := currentPool[j]
 				pairID := [2]int{c1.ID, c2.ID}
 				if c1.ID > c2.ID {
 					pairID = [2]int{c2.ID, c1.ID}
@@ -638,8 +902,10 @@ func (e *ResolutionEngine) Prove() ProofResult {
 
 					if !isDuplicate {
 						activeClauses = append(activeClauses, resolvent)
+						if useSupport {
+							supported[resolvent.ID] = true
+						}
 						progress = true
-
 						isContradiction := resolvent.IsEmpty()
 						stepName := fmt.Sprintf("Шаг %d - ", stepCount)
 						if isContradiction {
@@ -659,7 +925,8 @@ func (e *ResolutionEngine) Prove() ProofResult {
 							logLines = append(logLines, "\nРезультат: Доказано (□).")
 							chain := e.buildProofChain(resolvent)
 							shortLog := e.formatShortLog(chain)
-							return ProofResult{Success: true, FullLog: strings.Join(logLines, "\n"), ShortLog: shortLog}
+							return ProofResult{Success: true, FullLog: strings.Join(logLines, "\n"), ShortLog: shortLog, Chain: chain,
+								Checks: processedChecks, Generated: stepCount - 1}
 						}
 					}
 				}
@@ -668,7 +935,8 @@ func (e *ResolutionEngine) Prove() ProofResult {
 
 		if !progress {
 			logLines = append(logLines, "\nРезультат: Противоречие не найдено (база непротиворечива).")
-			return ProofResult{Success: false, FullLog: strings.Join(logLines, "\n"), ShortLog: strings.Join(logLines, "\n")}
+			return ProofResult{Success: false, FullLog: strings.Join(logLines, "\n"), ShortLog: strings.Join(logLines, "\n"),
+				Clauses: activeClauses, Checks: processedChecks, Generated: stepCount - 1}
 		}
 	}
 }
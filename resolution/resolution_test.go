@@ -1,6 +1,9 @@
 package resolution
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // помощник для запуска одного тестового случая
 func runCase(t *testing.T, name string, clauses []string, want bool) {
@@ -134,3 +137,123 @@ func TestArityMismatchPreventsUnify(t *testing.T) {
 		"¬Q(A)",
 	}, false)
 }
+
+func TestIncrementalQuery(t *testing.T) {
+	// База знаний разбирается один раз, затем задаются несколько вопросов подряд.
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"¬Викинг(x) ∨ Храбр(x)",
+		"¬Храбр(x) ∨ ПопадаетВВальгаллу(x)",
+		"Викинг(Рагнар)",
+	})
+
+	if res := engine.Query([]string{"¬ПопадаетВВальгаллу(Рагнар)"}); !res.Success {
+		t.Fatalf("first query: expected success\nFullLog:\n%s", res.FullLog)
+	}
+	if res := engine.Query([]string{"¬Храбр(Бьорн)"}); res.Success {
+		t.Fatalf("second query: expected failure\nFullLog:\n%s", res.FullLog)
+	}
+
+	// Цели не попадают в базу знаний.
+	if got := len(engine.Clauses()); got != 3 {
+		t.Fatalf("expected 3 clauses in knowledge base, got %d", got)
+	}
+
+	// Вопросы не расходуют номера клауз базы знаний
+	first := engine.Query([]string{"¬ПопадаетВВальгаллу(Рагнар)"})
+	if again := engine.Query([]string{"¬ПопадаетВВальгаллу(Рагнар)"}); again.ShortLog != first.ShortLog {
+		t.Errorf("repeated query must number clauses the same way:\n%s\n---\n%s", first.ShortLog, again.ShortLog)
+	}
+
+	// Насыщение базы знаний переиспользуется между вопросами
+	background := engine.background
+	if background == nil || len(background.clauses) <= 3 {
+		t.Fatalf("expected saturated background after queries, got %+v", background)
+	}
+	engine.Query([]string{"¬Храбр(Рагнар)"})
+	if engine.background != background {
+		t.Error("saturated background must be reused between queries")
+	}
+
+	added := engine.AddClauses([]string{"Викинг(Бьорн)"})
+	if added[0].ID != 4 {
+		t.Errorf("queries must not consume clause IDs, got ID %d for the fourth clause", added[0].ID)
+	}
+	if engine.background != nil {
+		t.Error("AddClauses must invalidate the saturated background")
+	}
+	if res := engine.Query([]string{"¬Храбр(Бьорн)"}); !res.Success {
+		t.Fatalf("query after AddClauses: expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	if err := engine.Retract(added[0].ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if engine.background != nil {
+		t.Error("Retract must invalidate the saturated background")
+	}
+	if res := engine.Query([]string{"¬Храбр(Бьорн)"}); res.Success {
+		t.Fatalf("query after Retract: expected failure\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestRetractUnknownClause(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"Человек(Сократ)"})
+	if err := engine.Retract(42); err == nil {
+		t.Fatal("expected error for unknown clause ID, got nil")
+	}
+	if got := len(engine.Clauses()); got != 1 {
+		t.Fatalf("knowledge base must stay intact, got %d clauses", got)
+	}
+}
+
+func TestRolesSeparateGoalFromPremises(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+
+	// Без цели посылки непротиворечивы.
+	if res := engine.CheckConsistency(); !res.Consistent {
+		t.Fatalf("goal clause must not take part in consistency check\nLog:\n%s", res.Log)
+	}
+
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+	if !strings.Contains(res.ShortLog, "¬Смертен(Сократ) (отрицание цели)") {
+		t.Errorf("short log must mark the negated goal:\n%s", res.ShortLog)
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, s := range []string{"axiom", "hypothesis", "negated_conjecture"} {
+		if _, err := ParseRole(s); err != nil {
+			t.Errorf("ParseRole(%q): unexpected error: %v", s, err)
+		}
+	}
+	if _, err := ParseRole(""); err == nil {
+		t.Error("expected error for missing role")
+	}
+	if _, err := ParseRole("conjecture"); err == nil {
+		t.Error("expected error for unsupported role")
+	}
+}
+
+func TestMaxIterationsLimit(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.MaxIterations = 100
+	engine.ParseInput([]string{"P(А)", "¬P(x) ∨ P(F(x))", "¬Q(А)"})
+
+	res := engine.Prove()
+	if res.Success || !res.LimitReached {
+		t.Fatalf("expected the iteration limit to stop the search\nFullLog:\n%s", res.FullLog)
+	}
+	if res.Checks != 100 {
+		t.Errorf("expected 100 checks, got %d", res.Checks)
+	}
+}
@@ -0,0 +1,93 @@
+package resolution
+
+// ==========================================
+// Сокращение доказательства
+// ==========================================
+
+// shorten ищет доказательство с меньшим числом шагов среди посылок уже
+// найденного: поиск в ширину находит вывод минимальной глубины, но не
+// минимального размера (сбалансированное дерево из 7 шагов вместо цепочки
+// из 4). Полный лог остаётся от исходного поиска.
+func (e *ResolutionEngine) shorten(result ProofResult) ProofResult {
+	if !result.Success {
+		return result
+	}
+	if chain := e.smallestProof(result.Premises(), proofSteps(result.Chain)-1); chain != nil {
+		result.Chain = chain
+		result.ShortLog = e.formatShortLog(chain)
+	}
+	return result
+}
+
+// smallestProof перебирает резольвенты в порядке размера вывода (число шагов
+// в дереве) и возвращает первое найденное опровержение размера не больше bound.
+// Клаузы хранятся по размерам, поэтому на каждом уровне проверяются только
+// пары, дающие резольвенту этого размера; бюджет shorten_iterations считает
+// каждую проверенную пару. Резольвенты нумеруются отдельным счётчиком, и лишь
+// шаги найденного доказательства получают номера движка, идущие подряд.
+func (e *ResolutionEngine) smallestProof(premises []*Clause, bound int) []*Clause {
+	scratch := &ResolutionEngine{clauseCounter: e.clauseCounter}
+	pool := append([]*Clause(nil), premises...)
+	bySize := [][]*Clause{pool}
+
+	checks := 0
+	for size := 1; size <= bound; size++ {
+		var level []*Clause
+		// Резольвента клауз размеров a и b имеет размер a+b+1
+		for a := 0; a <= (size-1)/2; a++ {
+			b := size - 1 - a
+			for i, c1 := range bySize[a] {
+				others := bySize[b]
+				if a == b {
+					others = others[i+1:]
+				}
+				for _, c2 := range others {
+					checks++
+					if checks > shorten_iterations {
+						return nil
+					}
+
+					for _, resolvent := range scratch.resolvePair(c1, c2) {
+						if resolvent.IsEmpty() {
+							return e.renumberChain(e.buildProofChain(resolvent))
+						}
+						isDuplicate := false
+						for _, existing := range pool {
+							if resolvent.Equal(existing) {
+								isDuplicate = true
+								break
+							}
+						}
+						if !isDuplicate {
+							pool = append(pool, resolvent)
+							level = append(level, resolvent)
+						}
+					}
+				}
+			}
+		}
+		bySize = append(bySize, level)
+	}
+	return nil
+}
+
+// renumberChain даёт шагам резолюции цепочки номера движка по порядку.
+func (e *ResolutionEngine) renumberChain(chain []*Clause) []*Clause {
+	for _, c := range chain {
+		if c.Origin == "res" {
+			c.ID = e.getNextID()
+		}
+	}
+	return chain
+}
+
+// proofSteps — число шагов резолюции в цепочке доказательства.
+func proofSteps(chain []*Clause) int {
+	steps := 0
+	for _, c := range chain {
+		if c.Origin == "res" {
+			steps++
+		}
+	}
+	return steps
+}
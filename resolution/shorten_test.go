@@ -0,0 +1,61 @@
+package resolution
+
+import "testing"
+
+func TestProveReturnsShortestProof(t *testing.T) {
+	// Поиск в ширину сначала находит доказательство из 6 шагов,
+	// хотя противоречие выводится за 4.
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"Г(О)",
+		"¬А(О) ∨ В(О) ∨ ¬Г(О)",
+		"¬Б(О)",
+		"¬Г(О) ∨ Б(О) ∨ А(О)",
+		"А(О) ∨ ¬Б(О)",
+		"¬В(О) ∨ Б(О)",
+		"В(О) ∨ ¬Г(О) ∨ ¬Б(О)",
+	})
+
+	raw := engine.search(engine.clauses, nil, max_iterations)
+	if !raw.Success || proofSteps(raw.Chain) != 6 {
+		t.Fatalf("expected breadth-first proof of 6 steps, got %d", proofSteps(raw.Chain))
+	}
+
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+	if got := proofSteps(res.Chain); got != 4 {
+		t.Fatalf("expected proof of 4 steps, got %d\nShortLog:\n%s", got, res.ShortLog)
+	}
+	if !res.Chain[len(res.Chain)-1].IsEmpty() {
+		t.Fatal("shortened chain must end with the empty clause")
+	}
+
+	// Перебор при сокращении не должен оставлять пропусков в нумерации шагов
+	var ids []int
+	for _, c := range res.Chain {
+		if c.Origin == "res" {
+			ids = append(ids, c.ID)
+		}
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] != ids[i-1]+1 {
+			t.Fatalf("proof steps must be numbered consecutively, got %v", ids)
+		}
+	}
+}
+
+func TestShortenKeepsOptimalProof(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{
+		"Человек(Сократ)",
+		"¬Человек(x) ∨ Смертен(x)",
+		"¬Смертен(Сократ)",
+	})
+
+	res := engine.Prove()
+	if got := proofSteps(res.Chain); got != 2 {
+		t.Fatalf("expected proof of 2 steps, got %d\nShortLog:\n%s", got, res.ShortLog)
+	}
+}
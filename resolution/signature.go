@@ -0,0 +1,58 @@
+package resolution
+
+import (
+	"fmt"
+	"sort"
+)
+
+// signature собирает символы задачи и проверяет их согласованность: каждое
+// имя — либо предикат, либо функция (константа), с одним числом аргументов.
+type signature struct {
+	arity      map[string]int
+	predicates map[string]bool
+}
+
+func newSignature() *signature {
+	return &signature{arity: make(map[string]int), predicates: make(map[string]bool)}
+}
+
+func (s *signature) add(sym Symbol) error {
+	predicate := sym.Kind == SymbolPredicate
+	if prev, ok := s.arity[sym.Name]; ok {
+		if s.predicates[sym.Name] != predicate {
+			return fmt.Errorf("%s используется и как предикат, и как функция", sym.Name)
+		}
+		if prev != sym.Arity {
+			return fmt.Errorf("%s используется с разным числом аргументов (%d и %d)", sym.Name, prev, sym.Arity)
+		}
+		return nil
+	}
+	s.arity[sym.Name] = sym.Arity
+	s.predicates[sym.Name] = predicate
+	return nil
+}
+
+// addLiteral добавляет предикат литерала и все символы его аргументов.
+func (s *signature) addLiteral(l *Literal) error {
+	for _, sym := range literalSymbols(l) {
+		if err := s.add(sym); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedNames — сначала константы и функции, затем предикаты, по алфавиту.
+func (s *signature) sortedNames() []string {
+	names := make([]string, 0, len(s.arity))
+	for name := range s.arity {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.predicates[names[i]] != s.predicates[names[j]] {
+			return !s.predicates[names[i]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,116 @@
+package resolution
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ==========================================
+// Экспорт задачи в SMT-LIB 2
+// ==========================================
+
+// WriteSMTLIB выгружает базу знаний как скрипт SMT-LIB 2: все термы имеют
+// один сорт U, предикаты и функции объявляются неинтерпретированными
+// символами, каждая клауза — именованное утверждение с кванторами ∀ по её
+// переменным. Скрипт заканчивается (check-sat): unsat означает, что
+// доказательство существует.
+func (e *ResolutionEngine) WriteSMTLIB(w io.Writer) error {
+	sig := newSignature()
+	for _, c := range e.clauses {
+		for _, lit := range c.Literals {
+			for _, sym := range literalSymbols(lit) {
+				if strings.ContainsAny(sym.Name, `|\`) {
+					return fmt.Errorf("клауза [%d]: имя %q нельзя записать в SMT-LIB", c.ID, sym.Name)
+				}
+			}
+			if err := sig.addLiteral(lit); err != nil {
+				return fmt.Errorf("клауза [%d]: %w", c.ID, err)
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; neurosolver")
+	fmt.Fprintln(bw, "(set-option :produce-unsat-cores true)")
+	fmt.Fprintln(bw, "(set-logic UF)")
+	fmt.Fprintln(bw, "(declare-sort U 0)")
+	for _, name := range sig.sortedNames() {
+		result := "U"
+		if sig.predicates[name] {
+			result = "Bool"
+		}
+		params := strings.TrimSpace(strings.Repeat("U ", sig.arity[name]))
+		fmt.Fprintf(bw, "(declare-fun %s (%s) %s)\n", smtSymbol(name), params, result)
+	}
+
+	for _, c := range e.clauses {
+		if c.Source != "" {
+			fmt.Fprintf(bw, "; %s\n", strings.Join(strings.Fields(c.Source), " "))
+		}
+		fmt.Fprintf(bw, "(assert (! %s :named c%d))\n", smtClause(c), c.ID)
+	}
+	fmt.Fprintln(bw, "(check-sat)")
+	return bw.Flush()
+}
+
+func smtSymbol(name string) string { return "|" + name + "|" }
+
+func smtClause(c *Clause) string {
+	if c.IsEmpty() {
+		return "false"
+	}
+
+	var vars []string
+	seen := make(map[string]bool)
+	parts := make([]string, len(c.Literals))
+	for i, lit := range c.Literals {
+		atom := smtApply(lit.Predicate, lit.Args, func(name string) {
+			if !seen[name] {
+				seen[name] = true
+				vars = append(vars, name)
+			}
+		})
+		if lit.Negated {
+			atom = "(not " + atom + ")"
+		}
+		parts[i] = atom
+	}
+
+	body := parts[0]
+	if len(parts) > 1 {
+		body = "(or " + strings.Join(parts, " ") + ")"
+	}
+	if len(vars) == 0 {
+		return body
+	}
+	binders := make([]string, len(vars))
+	for i, v := range vars {
+		binders[i] = "(" + smtSymbol(v) + " U)"
+	}
+	return "(forall (" + strings.Join(binders, " ") + ") " + body + ")"
+}
+
+// smtApply записывает применение символа; onVar вызывается для каждой переменной.
+func smtApply(name string, args []Term, onVar func(string)) string {
+	if len(args) == 0 {
+		return smtSymbol(name)
+	}
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = smtTerm(a, onVar)
+	}
+	return "(" + smtSymbol(name) + " " + strings.Join(parts, " ") + ")"
+}
+
+func smtTerm(t Term, onVar func(string)) string {
+	switch v := t.(type) {
+	case *Variable:
+		onVar(v.name)
+		return smtSymbol(v.name)
+	case *Function:
+		return smtApply(v.name, v.args, onVar)
+	}
+	return smtSymbol(t.Name())
+}
@@ -0,0 +1,51 @@
+package resolution
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSMTLIB(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "¬Человек(x) ∨ Смертен(x)", Source: "Все люди смертны."},
+		{Text: "Человек(Сократ)"},
+		{Text: "Любит(x, Мать(x))"},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+
+	var buf bytes.Buffer
+	if err := engine.WriteSMTLIB(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"(set-logic UF)\n(declare-sort U 0)\n",
+		"(declare-fun |Сократ| () U)\n",
+		"(declare-fun |Мать| (U) U)\n",
+		"(declare-fun |Любит| (U U) Bool)\n",
+		"; Все люди смертны.\n",
+		"(assert (! (forall ((|x| U)) (or (not (|Человек| |x|)) (|Смертен| |x|))) :named c1))\n",
+		"(assert (! (|Человек| |Сократ|) :named c2))\n",
+		"(assert (! (not (|Смертен| |Сократ|)) :named c4))\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "(check-sat)\n") {
+		t.Errorf("script must end with (check-sat):\n%s", out)
+	}
+	if strings.Count(out, "(") != strings.Count(out, ")") {
+		t.Errorf("unbalanced parentheses:\n%s", out)
+	}
+}
+
+func TestWriteSMTLIBArityMismatch(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A, B)", "¬P(A)"})
+	if err := engine.WriteSMTLIB(&bytes.Buffer{}); err == nil {
+		t.Fatal("expected error for inconsistent arity, got nil")
+	}
+}
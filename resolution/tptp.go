@@ -0,0 +1,611 @@
+package resolution
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ==========================================
+// Импорт и экспорт в формате TPTP (cnf/fof)
+// ==========================================
+//
+// Имена предикатов, функций и констант, не являющиеся lower_word TPTP
+// (например, кириллические), записываются в одинарных кавычках: 'Сократ'.
+// При чтении кавычки снимаются, поэтому имена сохраняются в обе стороны.
+// Переменные движка x, y, z записываются как X, Y, Z; при чтении переменные
+// TPTP получают однобуквенные имена в порядке появления в клаузе.
+// Однобуквенные строчные константы TPTP (a, x) движок прочёл бы
+// как переменные, поэтому при чтении они получают заглавное имя (A, X),
+// а при занятости — с числовым суффиксом (X1, X2, …).
+
+var tptpLowerWord = regexp.MustCompile(`^[a-z][A-Za-z0-9_]*$`)
+
+// LoadTPTP читает задачу в формате TPTP (формулы cnf и fof) и добавляет
+// полученные клаузы в базу знаний. Роли conjecture отрицаются и получают
+// роль RoleNegatedConjecture, hypothesis и assumption становятся
+// RoleHypothesis, остальные — RoleAxiom. Имя формулы сохраняется в Source.
+func (e *ResolutionEngine) LoadTPTP(r io.Reader) ([]*Clause, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения TPTP: %w", err)
+	}
+	toks, err := tokenizeTPTP(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := newTPTPParser(toks)
+	var formulas []tptpFormula
+	for !p.at(tptpEOF, "") {
+		f, err := p.parseAnnotated()
+		if err != nil {
+			return nil, err
+		}
+		formulas = append(formulas, f)
+	}
+
+	cl := newClausifier()
+	for _, f := range formulas {
+		cl.reserve(f.formula)
+	}
+
+	var added []*Clause
+	for _, f := range formulas {
+		for _, lits := range cl.clausify(f.formula) {
+			c := NewClause(e.getNextID(), lits, "init", [2]*Clause{}, "")
+			c.Role = f.role
+			c.Source = f.name
+			added = append(added, c)
+		}
+	}
+	e.clauses = append(e.clauses, added...)
+	e.background = nil
+	return added, nil
+}
+
+// WriteTPTP выгружает базу знаний в формате TPTP CNF. Исходное предложение
+// клаузы записывается комментарием перед ней.
+func (e *ResolutionEngine) WriteTPTP(w io.Writer) error {
+	for _, c := range e.clauses {
+		if c.Source != "" {
+			if _, err := fmt.Fprintf(w, "%% %s\n", strings.Join(strings.Fields(c.Source), " ")); err != nil {
+				return err
+			}
+		}
+		role := c.Role
+		if role == "" {
+			role = RoleAxiom
+		}
+		if _, err := fmt.Fprintf(w, "cnf(c%d, %s, %s).\n", c.ID, role, tptpClause(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tptpClause(c *Clause) string {
+	return tptpClauseVars(c, make(map[string]string))
+}
+
+// tptpClauseVars записывает клаузу, запоминая в vars имена её переменных в TPTP.
+func tptpClauseVars(c *Clause, vars map[string]string) string {
+	if c.IsEmpty() {
+		return "$false"
+	}
+	parts := make([]string, len(c.Literals))
+	for i, lit := range c.Literals {
+		prefix := ""
+		if lit.Negated {
+			prefix = "~"
+		}
+		parts[i] = prefix + tptpAtom(lit.Predicate, lit.Args, vars)
+	}
+	return "(" + strings.Join(parts, " | ") + ")"
+}
+
+func tptpAtom(name string, args []Term, vars map[string]string) string {
+	if len(args) == 0 {
+		return tptpName(name)
+	}
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = tptpTerm(a, vars)
+	}
+	return tptpName(name) + "(" + strings.Join(parts, ",") + ")"
+}
+
+func tptpTerm(t Term, vars map[string]string) string {
+	switch v := t.(type) {
+	case *Variable:
+		return tptpVariable(v.name, vars)
+	case *Function:
+		return tptpAtom(v.name, v.args, vars)
+	}
+	return tptpName(t.Name())
+}
+
+// tptpVariable: латинская буква переходит в заглавную, остальные имена
+// нумеруются V1, V2... в пределах клаузы.
+func tptpVariable(name string, vars map[string]string) string {
+	if v, ok := vars[name]; ok {
+		return v
+	}
+	var v string
+	if len(name) == 1 && name[0] >= 'a' && name[0] <= 'z' {
+		v = strings.ToUpper(name)
+	} else {
+		v = fmt.Sprintf("V%d", len(vars)+1)
+	}
+	vars[name] = v
+	return v
+}
+
+func tptpName(name string) string {
+	if tptpLowerWord.MatchString(name) {
+		return name
+	}
+	escaped := strings.ReplaceAll(name, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `'`, `\'`)
+	return "'" + escaped + "'"
+}
+
+// ==========================================
+// Лексер TPTP
+// ==========================================
+
+type tptpTokenKind int
+
+const (
+	tptpEOF tptpTokenKind = iota
+	tptpLower
+	tptpUpper
+	tptpQuoted
+	tptpDistinct
+	tptpDollar
+	tptpNumber
+	tptpPunct
+)
+
+type tptpToken struct {
+	kind tptpTokenKind
+	text string
+	line int
+}
+
+// Знаки упорядочены по убыванию длины: при разборе берётся самый длинный.
+var tptpPuncts = []string{
+	"<=>", "<~>",
+	"=>", "<=", "~|", "~&", "!=",
+	"(", ")", "[", "]", ",", ".", ":", "!", "?", "~", "&", "|", "=",
+}
+
+func tokenizeTPTP(src string) ([]tptpToken, error) {
+	var toks []tptpToken
+	line := 1
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i += size
+		case r == '%':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("TPTP, строка %d: незакрытый комментарий", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case r == '\'' || r == '"':
+			text, n, err := readTPTPQuoted(src[i:], byte(r))
+			if err != nil {
+				return nil, fmt.Errorf("TPTP, строка %d: %v", line, err)
+			}
+			kind := tptpQuoted
+			if r == '"' {
+				kind, text = tptpDistinct, src[i:i+n]
+			}
+			toks = append(toks, tptpToken{kind: kind, text: text, line: line})
+			i += n
+		case isTPTPWordRune(r) || r == '$':
+			j := i + size
+			for j < len(src) {
+				r2, s2 := utf8.DecodeRuneInString(src[j:])
+				if !isTPTPWordRune(r2) {
+					break
+				}
+				j += s2
+			}
+			word := src[i:j]
+			kind := tptpLower
+			switch {
+			case r == '$':
+				kind = tptpDollar
+			case unicode.IsDigit(r):
+				kind = tptpNumber
+			case unicode.IsUpper(r):
+				kind = tptpUpper
+			}
+			toks = append(toks, tptpToken{kind: kind, text: word, line: line})
+			i = j
+		default:
+			matched := false
+			for _, p := range tptpPuncts {
+				if strings.HasPrefix(src[i:], p) {
+					toks = append(toks, tptpToken{kind: tptpPunct, text: p, line: line})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("TPTP, строка %d: неожиданный символ %q", line, r)
+			}
+		}
+	}
+	return append(toks, tptpToken{kind: tptpEOF, line: line}), nil
+}
+
+func isTPTPWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// readTPTPQuoted читает строку в кавычках quote, снимая экранирование \\ и \'.
+// Возвращает содержимое и число прочитанных байт.
+func readTPTPQuoted(s string, quote byte) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("незакрытая строка")
+			}
+			i++
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		case '\n':
+			return "", 0, fmt.Errorf("перенос строки внутри кавычек")
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("незакрытая строка")
+}
+
+// ==========================================
+// Парсер TPTP
+// ==========================================
+
+type tptpFormula struct {
+	name    string
+	role    Role
+	formula *formula
+}
+
+type tptpParser struct {
+	toks    []tptpToken
+	pos     int
+	used    map[string]bool   // имена всех символов задачи
+	renamed map[string]string // однобуквенные строчные имена → заглавные
+}
+
+func newTPTPParser(toks []tptpToken) *tptpParser {
+	p := &tptpParser{toks: toks, used: make(map[string]bool), renamed: make(map[string]string)}
+	for _, t := range toks {
+		switch t.kind {
+		case tptpLower, tptpQuoted, tptpNumber, tptpDistinct:
+			p.used[t.text] = true
+		}
+	}
+	return p
+}
+
+// constantName возвращает имя, под которым константа TPTP
+// попадает в движок: имя из одной строчной буквы совпало бы с переменной,
+// поэтому оно заменяется заглавным, не занятым другими символами задачи.
+func (p *tptpParser) constantName(name string) string {
+	if !isSingleLowerLetter(name) {
+		return name
+	}
+	if r, ok := p.renamed[name]; ok {
+		return r
+	}
+	base := strings.ToUpper(name)
+	r := base
+	for i := 1; p.used[r]; i++ {
+		r = fmt.Sprintf("%s%d", base, i)
+	}
+	p.used[r] = true
+	p.renamed[name] = r
+	return r
+}
+
+func (p *tptpParser) peek() tptpToken { return p.toks[p.pos] }
+
+func (p *tptpParser) next() tptpToken {
+	t := p.toks[p.pos]
+	if t.kind != tptpEOF {
+		p.pos++
+	}
+	return t
+}
+
+// at проверяет вид текущего токена (и текст, если он задан).
+func (p *tptpParser) at(kind tptpTokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && (text == "" || t.text == text)
+}
+
+func (p *tptpParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	near := t.text
+	if t.kind == tptpEOF {
+		near = "конец файла"
+	}
+	return fmt.Errorf("TPTP, строка %d (около %q): %s", t.line, near, fmt.Sprintf(format, args...))
+}
+
+func (p *tptpParser) expect(text string) error {
+	if !p.at(tptpPunct, text) {
+		return p.errorf("ожидалось %q", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *tptpParser) parseAnnotated() (tptpFormula, error) {
+	var result tptpFormula
+	if !p.at(tptpLower, "") {
+		return result, p.errorf("ожидалось cnf(...) или fof(...)")
+	}
+	lang := p.next().text
+	switch lang {
+	case "cnf", "fof":
+	case "include":
+		return result, p.errorf("директива include не поддерживается")
+	default:
+		return result, p.errorf("формат %s не поддерживается (только cnf и fof)", lang)
+	}
+
+	if err := p.expect("("); err != nil {
+		return result, err
+	}
+	name := p.next()
+	switch name.kind {
+	case tptpLower, tptpQuoted, tptpNumber:
+		result.name = name.text
+	default:
+		return result, p.errorf("ожидалось имя формулы")
+	}
+	if err := p.expect(","); err != nil {
+		return result, err
+	}
+	if !p.at(tptpLower, "") {
+		return result, p.errorf("ожидалась роль формулы")
+	}
+	role := p.next().text
+	if err := p.expect(","); err != nil {
+		return result, err
+	}
+
+	f, err := p.parseFormula()
+	if err != nil {
+		return result, err
+	}
+
+	// Аннотации (источник, useful_info) пропускаем
+	depth := 0
+	for depth > 0 || !p.at(tptpPunct, ")") {
+		t := p.next()
+		switch {
+		case t.kind == tptpEOF:
+			return result, p.errorf("незакрытая формула %s", result.name)
+		case t.text == "(" || t.text == "[":
+			depth++
+		case t.text == ")" || t.text == "]":
+			depth--
+		}
+	}
+	p.next()
+	if err := p.expect("."); err != nil {
+		return result, err
+	}
+
+	switch role {
+	case "conjecture":
+		result.role = RoleNegatedConjecture
+		f = notFormula(universalClosure(f))
+	case "negated_conjecture":
+		result.role = RoleNegatedConjecture
+	case "hypothesis", "assumption":
+		result.role = RoleHypothesis
+	default:
+		result.role = RoleAxiom
+	}
+	result.formula = f
+	return result, nil
+}
+
+func (p *tptpParser) parseFormula() (*formula, error) {
+	left, err := p.parseUnitary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.at(tptpPunct, "") {
+		return left, nil
+	}
+
+	switch op := p.peek().text; op {
+	case "&", "|":
+		fop := opAnd
+		if op == "|" {
+			fop = opOr
+		}
+		parts := []*formula{left}
+		for p.at(tptpPunct, op) {
+			p.next()
+			right, err := p.parseUnitary()
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, right)
+		}
+		return &formula{op: fop, sub: parts}, nil
+	case "=>", "<=", "<=>", "<~>", "~|", "~&":
+		p.next()
+		right, err := p.parseUnitary()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "=>":
+			return binaryFormula(opImplies, left, right), nil
+		case "<=":
+			return binaryFormula(opImplies, right, left), nil
+		case "<=>":
+			return binaryFormula(opIff, left, right), nil
+		case "<~>":
+			return notFormula(binaryFormula(opIff, left, right)), nil
+		case "~|":
+			return notFormula(binaryFormula(opOr, left, right)), nil
+		default:
+			return notFormula(binaryFormula(opAnd, left, right)), nil
+		}
+	}
+	return left, nil
+}
+
+func (p *tptpParser) parseUnitary() (*formula, error) {
+	switch {
+	case p.at(tptpPunct, "!"), p.at(tptpPunct, "?"):
+		op := opForall
+		if p.next().text == "?" {
+			op = opExists
+		}
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		var vars []string
+		for {
+			if !p.at(tptpUpper, "") {
+				return nil, p.errorf("ожидалась переменная")
+			}
+			vars = append(vars, p.next().text)
+			if !p.at(tptpPunct, ",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		body, err := p.parseUnitary()
+		if err != nil {
+			return nil, err
+		}
+		return quantFormula(op, vars, body), nil
+	case p.at(tptpPunct, "~"):
+		p.next()
+		f, err := p.parseUnitary()
+		if err != nil {
+			return nil, err
+		}
+		return notFormula(f), nil
+	case p.at(tptpPunct, "("):
+		p.next()
+		f, err := p.parseFormula()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return f, nil
+	case p.at(tptpDollar, "$true"):
+		p.next()
+		return &formula{op: opTrue}, nil
+	case p.at(tptpDollar, "$false"):
+		p.next()
+		return &formula{op: opFalse}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *tptpParser) parseAtom() (*formula, error) {
+	if !p.at(tptpLower, "") && !p.at(tptpQuoted, "") {
+		if p.at(tptpUpper, "") {
+			return nil, p.errorf("равенство и переменные-предикаты не поддерживаются")
+		}
+		return nil, p.errorf("ожидался атом")
+	}
+	name := p.next().text
+	var args []Term
+	if p.at(tptpPunct, "(") {
+		var err error
+		if args, err = p.parseArgs(); err != nil {
+			return nil, err
+		}
+	}
+	if p.at(tptpPunct, "=") || p.at(tptpPunct, "!=") {
+		return nil, p.errorf("равенство не поддерживается")
+	}
+	return atomFormula(name, args), nil
+}
+
+func (p *tptpParser) parseArgs() ([]Term, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []Term
+	for {
+		t, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, t)
+		if !p.at(tptpPunct, ",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (p *tptpParser) parseTerm() (Term, error) {
+	t := p.peek()
+	switch t.kind {
+	case tptpUpper:
+		p.next()
+		return NewVariable(t.text), nil
+	case tptpNumber, tptpDistinct:
+		p.next()
+		return NewConstant(t.text), nil
+	case tptpLower, tptpQuoted:
+		p.next()
+		if p.at(tptpPunct, "(") {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return NewFunction(t.text, args), nil
+		}
+		return NewConstant(p.constantName(t.text)), nil
+	}
+	return nil, p.errorf("ожидался терм")
+}
@@ -0,0 +1,145 @@
+package resolution
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTPTPRoundTrip(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "¬Человек(x) ∨ Смертен(x)", Source: "Все люди смертны.", Role: RoleAxiom},
+		{Text: "Человек(Сократ)", Source: "Сократ — человек.", Role: RoleHypothesis},
+		{Text: "Отец(ФункОтец(x), x)", Role: RoleAxiom},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+
+	var buf bytes.Buffer
+	if err := engine.WriteTPTP(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"% Все люди смертны.",
+		"cnf(c1, axiom, (~'Человек'(X) | 'Смертен'(X))).",
+		"cnf(c2, hypothesis, ('Человек'('Сократ'))).",
+		"cnf(c4, negated_conjecture, (~'Смертен'('Сократ'))).",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	loaded := NewResolutionEngine()
+	clauses, err := loaded.LoadTPTP(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original := engine.Clauses()
+	if len(clauses) != len(original) {
+		t.Fatalf("got %d clauses, want %d", len(clauses), len(original))
+	}
+	for i := range original {
+		if clauses[i].String() != original[i].String() || clauses[i].Role != original[i].Role {
+			t.Errorf("clause %d: got %s (%s), want %s (%s)",
+				i, clauses[i], clauses[i].Role, original[i], original[i].Role)
+		}
+	}
+
+	if res := loaded.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestLoadTPTPFOF(t *testing.T) {
+	src := `
+% Классический силлогизм
+fof(humans_are_mortal, axiom, ![X]: (human(X) => mortal(X))).
+fof(socrates, hypothesis, human(socrates)).
+/* цель */
+fof(goal, conjecture, ?[Y]: mortal(Y), file('syllogism.p')).
+`
+	engine := NewResolutionEngine()
+	clauses, err := engine.LoadTPTP(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var texts []string
+	for _, c := range clauses {
+		texts = append(texts, c.String())
+	}
+	want := []string{"mortal(x) ∨ ¬human(x)", "human(socrates)", "¬mortal(x)"}
+	if strings.Join(texts, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("got %q, want %q", texts, want)
+	}
+	if !clauses[2].IsGoal() || clauses[1].Role != RoleHypothesis || clauses[2].Source != "goal" {
+		t.Errorf("unexpected roles or sources: %+v", clauses)
+	}
+
+	if res := engine.Prove(); !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestLoadTPTPSkolemization(t *testing.T) {
+	// Каждый любит кого-то: ∃ под ∀ даёт скулемовскую функцию от x.
+	src := `fof(a1, axiom, ![X]: ?[Y]: loves(X, Y)).
+fof(a2, axiom, ?[Z]: ~loves(Z, Z) | p).`
+	engine := NewResolutionEngine()
+	clauses, err := engine.LoadTPTP(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clauses) != 2 {
+		t.Fatalf("expected 2 clauses, got %d", len(clauses))
+	}
+	if got := clauses[0].String(); got != "loves(x, Ск1(x))" {
+		t.Errorf("got %q, want skolem function of x", got)
+	}
+	if got := clauses[1].String(); got != "p() ∨ ¬loves(Конст2, Конст2)" {
+		t.Errorf("got %q, want skolem constant", got)
+	}
+}
+
+func TestLoadTPTPSingleLetterConstants(t *testing.T) {
+	// Константы x и y не должны унифицироваться как переменные:
+	// p(x) и ¬q(y) говорят о разных объектах, задача выполнима.
+	src := `cnf(a1, axiom, ~p(X) | q(X)).
+cnf(a2, axiom, p(x)).
+cnf(g, negated_conjecture, ~q(y)).
+cnf(a3, axiom, r(x, 'X', f(a), A)).`
+	engine := NewResolutionEngine()
+	clauses, err := engine.LoadTPTP(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := clauses[1].String(); got != "p(X1)" {
+		t.Errorf("got %q, want renamed constant p(X1)", got)
+	}
+	if got := clauses[3].String(); got != "r(X1, X, f(A), x)" {
+		t.Errorf("got %q, want r(X1, X, f(A), x)", got)
+	}
+	if res := engine.Prove(); res.Success {
+		t.Fatalf("expected failure for satisfiable problem\nFullLog:\n%s", res.FullLog)
+	}
+}
+
+func TestLoadTPTPErrors(t *testing.T) {
+	cases := map[string]string{
+		"equality":    "cnf(e, axiom, a = b).",
+		"syntax":      "fof(s, axiom, (p(X) => ).",
+		"unsupported": "tff(t, axiom, p).",
+		"include":     "include('Axioms/SET001-0.ax').",
+	}
+	for name, src := range cases {
+		engine := NewResolutionEngine()
+		if _, err := engine.LoadTPTP(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+		if len(engine.Clauses()) != 0 {
+			t.Errorf("%s: clauses must not be added on error", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package resolution
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ==========================================
+// Вывод доказательства в формате TSTP
+// ==========================================
+
+// FormatTSTP записывает доказательство как вывод TSTP (CNFRefutation):
+// начальные клаузы с их ролями и шаги резолюции с аннотациями
+// inference(resolution, [status(thm)], [родители]). Подстановка шага
+// указывается у каждого родителя в виде bind(X, $fot(терм)).
+func FormatTSTP(result ProofResult) string {
+	var lines []string
+	if !result.Success {
+		status := "GaveUp"
+		if result.LimitReached {
+			status = "Timeout"
+		}
+		return fmt.Sprintf("%% SZS status %s\n", status)
+	}
+
+	status := "Unsatisfiable"
+	for _, c := range result.Chain {
+		if c.IsGoal() {
+			status = "Theorem"
+			break
+		}
+	}
+	lines = append(lines, "% SZS status "+status)
+	lines = append(lines, "% SZS output start CNFRefutation")
+
+	// Имена переменных TPTP каждой клаузы нужны для bind() в шагах-потомках
+	clauseVars := make(map[int]map[string]string, len(result.Chain))
+	for _, c := range result.Chain {
+		vars := make(map[string]string)
+		clauseVars[c.ID] = vars
+		body := tptpClauseVars(c, vars)
+
+		if c.Origin == "init" {
+			role := c.Role
+			if role == "" {
+				role = RoleAxiom
+			}
+			lines = append(lines, fmt.Sprintf("cnf(c%d, %s, %s).", c.ID, role, body))
+			continue
+		}
+
+		parents := make([]string, 2)
+		for i, parent := range c.Parents {
+			parents[i] = fmt.Sprintf("c%d", parent.ID)
+			if binds := tstpBinds(parent, c.Subst, clauseVars[parent.ID]); binds != "" {
+				parents[i] += ":[" + binds + "]"
+			}
+		}
+		lines = append(lines, fmt.Sprintf("cnf(c%d, plain, %s, inference(resolution, [status(thm)], [%s])).",
+			c.ID, body, strings.Join(parents, ", ")))
+	}
+
+	lines = append(lines, "% SZS output end CNFRefutation")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// tstpBinds перечисляет подстановки для переменных, входящих в клаузу parent.
+func tstpBinds(parent *Clause, subst Theta, vars map[string]string) string {
+	names := make([]string, 0, len(subst))
+	for name := range subst {
+		if _, ok := vars[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	binds := make([]string, len(names))
+	for i, name := range names {
+		binds[i] = fmt.Sprintf("bind(%s, $fot(%s))", vars[name], tptpTerm(subst[name], vars))
+	}
+	return strings.Join(binds, ", ")
+}
@@ -0,0 +1,49 @@
+package resolution
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTSTP(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.AddInput([]InputClause{
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	})
+	res := engine.Prove()
+	if !res.Success {
+		t.Fatalf("expected success\nFullLog:\n%s", res.FullLog)
+	}
+
+	out := FormatTSTP(res)
+	for _, want := range []string{
+		"% SZS status Theorem",
+		"% SZS output start CNFRefutation",
+		"cnf(c1, hypothesis, ('Человек'('Сократ'))).",
+		"cnf(c2, axiom, (~'Человек'(X) | 'Смертен'(X))).",
+		"cnf(c3, negated_conjecture, (~'Смертен'('Сократ'))).",
+		"[c2:[bind(X, $fot('Сократ'))], c3]",
+		"cnf(c5, plain, $false, inference(resolution, [status(thm)], [c1, c4])).",
+		"% SZS output end CNFRefutation",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	// Вывод TSTP остаётся корректным входом для нашего же читателя TPTP
+	loaded := NewResolutionEngine()
+	if _, err := loaded.LoadTPTP(strings.NewReader(out)); err != nil {
+		t.Fatalf("TSTP output is not valid TPTP: %v\n%s", err, out)
+	}
+}
+
+func TestFormatTSTPFailure(t *testing.T) {
+	engine := NewResolutionEngine()
+	engine.ParseInput([]string{"P(A)", "¬Q(A)"})
+	if out := FormatTSTP(engine.Prove()); out != "% SZS status GaveUp\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
@@ -0,0 +1,141 @@
+package resolution
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// ==========================================
+// Проверка клауз, полученных от LLM
+// ==========================================
+
+// ClauseError — ошибка в клаузе задачи. Index — номер клаузы с 1;
+// 0 — ошибка задачи в целом (например, нет отрицания цели).
+type ClauseError struct {
+	Index  int
+	Clause string
+	Msg    string
+}
+
+func (e ClauseError) Error() string {
+	if e.Index == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("клауза %d «%s»: %s", e.Index, e.Clause, e.Msg)
+}
+
+// ErrNoGoal — в задаче нет клаузы отрицания цели: без неё опровержение
+// доказывает лишь противоречивость посылок.
+var ErrNoGoal = errors.New("нет ни одной клаузы с ролью negated_conjecture (отрицание цели)")
+
+// ValidateInput проверяет клаузы строже, чем AddInput, который молча
+// принимает почти любую строку: синтаксис клаузы, имена предикатов, функций
+// и констант на кириллице, одинаковое число аргументов у одного имени во
+// всей задаче и наличие хотя бы одной клаузы отрицания цели.
+func ValidateInput(inputs []InputClause) []ClauseError {
+	var errs []ClauseError
+	sig := newSignature()
+	hasGoal := false
+	for i, in := range inputs {
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, ClauseError{Index: i + 1, Clause: in.Text, Msg: fmt.Sprintf(format, args...)})
+		}
+		if in.Role == RoleNegatedConjecture {
+			hasGoal = true
+		}
+
+		p, err := newFormulaParser(in.Text)
+		if err != nil {
+			fail("%v", err)
+			continue
+		}
+		if !p.isClauseSyntax() {
+			fail("ожидалась клауза: литералы через '∨', без ∧, →, ↔ и кванторов")
+			continue
+		}
+		lits, err := p.parseClause()
+		if err != nil {
+			fail("%v", err)
+			continue
+		}
+		for _, l := range lits {
+			for _, sym := range literalSymbols(l) {
+				if !isCyrillicName(sym.Name) {
+					fail("имя %s должно быть записано кириллицей", sym.Name)
+				}
+			}
+			if err := sig.addLiteral(l); err != nil {
+				fail("%v", err)
+			}
+		}
+	}
+	if !hasGoal {
+		errs = append(errs, ClauseError{Msg: ErrNoGoal.Error()})
+	}
+	return errs
+}
+
+// Виды символов сигнатуры
+const (
+	SymbolPredicate = "predicate"
+	SymbolFunction  = "function"
+	SymbolConstant  = "constant"
+)
+
+// Symbol — предикат, функция или константа задачи.
+type Symbol struct {
+	Name  string
+	Kind  string
+	Arity int
+}
+
+// CollectSymbols перечисляет символы клауз в порядке первого появления.
+// Если имя встречается с разной арностью, учитывается первое вхождение.
+func CollectSymbols(clauses []*Clause) []Symbol {
+	var symbols []Symbol
+	seen := make(map[string]bool)
+	for _, c := range clauses {
+		for _, l := range c.Literals {
+			for _, sym := range literalSymbols(l) {
+				if !seen[sym.Name] {
+					seen[sym.Name] = true
+					symbols = append(symbols, sym)
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// literalSymbols перечисляет предикат, функции и константы литерала.
+func literalSymbols(l *Literal) []Symbol {
+	symbols := []Symbol{{Name: l.Predicate, Kind: SymbolPredicate, Arity: len(l.Args)}}
+	var walk func(t Term)
+	walk = func(t Term) {
+		switch v := t.(type) {
+		case *Variable:
+		case *Function:
+			symbols = append(symbols, Symbol{Name: v.name, Kind: SymbolFunction, Arity: len(v.args)})
+			for _, a := range v.args {
+				walk(a)
+			}
+		default:
+			symbols = append(symbols, Symbol{Name: t.Name(), Kind: SymbolConstant})
+		}
+	}
+	for _, a := range l.Args {
+		walk(a)
+	}
+	return symbols
+}
+
+// isCyrillicName: все буквы имени — кириллица (цифры и '_' допустимы).
+func isCyrillicName(name string) bool {
+	for _, r := range name {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Cyrillic, r) {
+			return false
+		}
+	}
+	return true
+}
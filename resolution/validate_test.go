@@ -0,0 +1,53 @@
+package resolution
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateInput(t *testing.T) {
+	valid := []InputClause{
+		{Text: "¬Человек(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "Человек(Сократ)", Role: RoleHypothesis},
+		{Text: "¬Смертен(Сократ)", Role: RoleNegatedConjecture},
+	}
+	if errs := ValidateInput(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	errs := ValidateInput([]InputClause{
+		{Text: "¬Human(x) ∨ Смертен(x)", Role: RoleAxiom},
+		{Text: "Смертен(Сократ, Платон)", Role: RoleHypothesis},
+		{Text: "Человек(x) ∧ Смертен(x)", Role: RoleAxiom},
+		{Text: "Человек(Сократ", Role: RoleHypothesis},
+	})
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		"клауза 1 «¬Human(x) ∨ Смертен(x)»: имя Human должно быть записано кириллицей",
+		"клауза 2 «Смертен(Сократ, Платон)»: Смертен используется с разным числом аргументов (1 и 2)",
+		"клауза 3 «Человек(x) ∧ Смертен(x)»: ожидалась клауза: литералы через '∨', без ∧, →, ↔ и кванторов",
+		"клауза 4 «Человек(Сократ»: позиция 15: ожидалось \")\", найдено конец строки",
+		"нет ни одной клаузы с ролью negated_conjecture (отрицание цели)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCollectSymbols(t *testing.T) {
+	engine := NewResolutionEngine()
+	clauses := engine.AddClauses([]string{"¬Человек(x) ∨ Любит(x, Мать(x))", "Человек(Сократ)"})
+
+	var got []string
+	for _, s := range CollectSymbols(clauses) {
+		got = append(got, fmt.Sprintf("%s %s/%d", s.Kind, s.Name, s.Arity))
+	}
+	want := "predicate Человек/1, predicate Любит/2, function Мать/1, constant Сократ/0"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ", "), want)
+	}
+}